@@ -1,8 +1,10 @@
 package main
 
 import (
+	"errors"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/allisson/go-env"
@@ -13,6 +15,7 @@ import (
 	"github.com/urfave/cli/v2"
 	"go.uber.org/zap"
 
+	"github.com/allisson/postmand"
 	_ "github.com/allisson/postmand/docs"
 	"github.com/allisson/postmand/http"
 	"github.com/allisson/postmand/http/handler"
@@ -77,21 +80,109 @@ func main() {
 				return migrationService.Run(c.Context)
 			},
 		},
+		{
+			Name:  "backfill-payload-json",
+			Usage: "copies the JSON payloads of the deliveries created before the payload search to the payload_json column",
+			Flags: []cli.Flag{
+				&cli.IntFlag{Name: "batch-size", Usage: "amount of deliveries updated by each statement", Value: 1000},
+			},
+			Action: func(c *cli.Context) error {
+				if c.Int("batch-size") < 1 {
+					return errors.New("batch-size must be greater than zero")
+				}
+				migrationRepository := repository.NewMigration(
+					db,
+					env.GetString("POSTMAND_DATABASE_MIGRATION_DIR", "file:///db/migrations"),
+				)
+				migrationService := service.NewMigration(migrationRepository, logger)
+				return migrationService.BackfillPayloadJSON(c.Context, c.Int("batch-size"))
+			},
+		},
 		{
 			Name:    "worker",
 			Aliases: []string{"w"},
 			Usage:   "executes worker to dispatch webhooks",
+			Flags: []cli.Flag{
+				&cli.StringSliceFlag{
+					Name:    "queues",
+					Usage:   "dispatch only deliveries of webhooks from these queues (defaults to all queues)",
+					EnvVars: []string{"POSTMAND_WORKER_QUEUES"},
+				},
+			},
 			Action: func(c *cli.Context) error {
 				// Start health check
 				go healthcheckServer(db, logger)
 
+				// Ignore empty values (POSTMAND_WORKER_QUEUES='' means all queues)
+				queues := []string{}
+				for _, queue := range c.StringSlice("queues") {
+					if queue != "" {
+						queues = append(queues, queue)
+					}
+				}
+
 				deliveryRepository := repository.NewDelivery(db)
+				replayJobRepository := repository.NewReplayJob(db)
 				pollingInterval := time.Duration(env.GetInt("POSTMAND_POLLING_INTERVAL", 1000)) * time.Millisecond
-				workerService := service.NewWorker(deliveryRepository, logger, pollingInterval)
+				workerService := service.NewWorker(deliveryRepository, replayJobRepository, logger, pollingInterval, queues)
 				workerService.Run(c.Context)
 				return nil
 			},
 		},
+		{
+			Name:    "replay",
+			Aliases: []string{"r"},
+			Usage:   "sends again the deliveries that match the filters",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "webhook-id", Usage: "filter by webhook_id"},
+				&cli.StringFlag{Name: "status", Usage: "filter by status (succeeded or failed)"},
+				&cli.StringFlag{Name: "created-at-gt", Usage: "filter by created_at greater than this value"},
+				&cli.StringFlag{Name: "created-at-gte", Usage: "filter by created_at greater than or equal to this value"},
+				&cli.StringFlag{Name: "created-at-lt", Usage: "filter by created_at less than this value"},
+				&cli.StringFlag{Name: "created-at-lte", Usage: "filter by created_at less than or equal to this value"},
+				&cli.BoolFlag{Name: "reset-delivery-attempts", Usage: "reset the delivery attempts counter of the deliveries"},
+			},
+			Action: func(c *cli.Context) error {
+				filters := postmand.ReplayJobFilters{}
+				for _, filterKey := range postmand.ReplayJobFilterKeys {
+					flagName := strings.ReplaceAll(strings.ReplaceAll(filterKey, "_", "-"), ".", "-")
+					if value := c.String(flagName); value != "" {
+						filters[filterKey] = value
+					}
+				}
+				replayJob := postmand.ReplayJob{
+					Filters:               filters,
+					ResetDeliveryAttempts: c.Bool("reset-delivery-attempts"),
+				}
+				if err := replayJob.Validate(); err != nil {
+					return err
+				}
+
+				replayJobRepository := repository.NewReplayJob(db)
+				replayJobService := service.NewReplayJob(replayJobRepository)
+				if err := replayJobService.Create(c.Context, &replayJob); err != nil {
+					return err
+				}
+				executedReplayJob, err := replayJobService.Run(c.Context, replayJob.ID)
+				if err != nil {
+					return err
+				}
+				// The replay job was already executed by a worker.
+				if executedReplayJob == nil {
+					logger.Info("replay-job-skipped", zap.String("id", replayJob.ID.String()))
+					return nil
+				}
+
+				logger.Info(
+					"replay-job-executed",
+					zap.String("id", executedReplayJob.ID.String()),
+					zap.String("status", executedReplayJob.Status),
+					zap.Int("replayed_deliveries", executedReplayJob.ReplayedDeliveries),
+					zap.String("error", executedReplayJob.Error),
+				)
+				return nil
+			},
+		},
 		{
 			Name:    "server",
 			Aliases: []string{"s"},
@@ -104,36 +195,74 @@ func main() {
 				webhookRepository := repository.NewWebhook(db)
 				deliveryRepository := repository.NewDelivery(db)
 				deliveryAttemptRepository := repository.NewDeliveryAttempt(db)
+				replayJobRepository := repository.NewReplayJob(db)
+				deadLetterRepository := repository.NewDeadLetter(db)
+				eventRepository := repository.NewEvent(db)
 
 				// Create services
 				webhookService := service.NewWebhook(webhookRepository)
-				deliveryService := service.NewDelivery(deliveryRepository)
+				idempotencyKeyRetention := time.Duration(
+					env.GetInt("POSTMAND_IDEMPOTENCY_KEY_RETENTION", int(postmand.DeliveryIdempotencyKeyRetention.Seconds())),
+				) * time.Second
+				maxPayloadSize := env.GetInt("POSTMAND_MAX_PAYLOAD_SIZE", postmand.DeliveryPayloadMaxSize)
+				deliveryService := service.NewDelivery(deliveryRepository, webhookRepository, idempotencyKeyRetention, maxPayloadSize)
 				deliveryAttemptService := service.NewDeliveryAttempt(deliveryAttemptRepository)
+				replayJobService := service.NewReplayJob(replayJobRepository)
+				deadLetterService := service.NewDeadLetter(deadLetterRepository)
+				eventService := service.NewEvent(eventRepository, webhookRepository, maxPayloadSize)
 
 				// Create http handlers
 				webhookHandler := handler.NewWebhook(webhookService, logger)
 				deliveryHandler := handler.NewDelivery(deliveryService, logger)
 				deliveryAttemptHandler := handler.NewDeliveryAttempt(deliveryAttemptService, logger)
+				replayJobHandler := handler.NewReplayJob(replayJobService, logger)
+				deadLetterHandler := handler.NewDeadLetter(deadLetterService, logger)
+				eventHandler := handler.NewEvent(eventService, logger)
 
 				httpPort := env.GetInt("POSTMAND_HTTP_PORT", 8000)
 				mux := http.NewRouter(logger)
 				mux.Route("/v1/webhooks", func(r chi.Router) {
 					r.Get("/", webhookHandler.List)
 					r.Post("/", webhookHandler.Create)
+					r.Post("/filter-dry-run", webhookHandler.FilterDryRun)
 					r.Get("/{webhook_id}", webhookHandler.Get)
 					r.Put("/{webhook_id}", webhookHandler.Update)
 					r.Delete("/{webhook_id}", webhookHandler.Delete)
+					r.Post("/{webhook_id}/test", webhookHandler.Test)
+					r.Post("/{webhook_id}/preview", webhookHandler.Preview)
+					r.Post("/{webhook_id}/verify", webhookHandler.Verify)
 				})
 				mux.Route("/v1/deliveries", func(r chi.Router) {
 					r.Get("/", deliveryHandler.List)
 					r.Post("/", deliveryHandler.Create)
 					r.Get("/{delivery_id}", deliveryHandler.Get)
 					r.Delete("/{delivery_id}", deliveryHandler.Delete)
+					r.Post("/{delivery_id}/cancel", deliveryHandler.Cancel)
+					r.Post("/{delivery_id}/retry", deliveryHandler.Retry)
+					r.Post("/{delivery_id}/replay-to-url", deliveryHandler.ReplayToURL)
+				})
+				mux.Route("/v1/events", func(r chi.Router) {
+					r.Get("/", eventHandler.List)
+					r.Post("/", eventHandler.Create)
+					r.Get("/{event_id}", eventHandler.Get)
 				})
 				mux.Route("/v1/delivery-attempts", func(r chi.Router) {
 					r.Get("/", deliveryAttemptHandler.List)
 					r.Get("/{delivery_attempt_id}", deliveryAttemptHandler.Get)
 				})
+				mux.Route("/v1/replay-jobs", func(r chi.Router) {
+					r.Get("/", replayJobHandler.List)
+					r.Post("/", replayJobHandler.Create)
+					r.Get("/{replay_job_id}", replayJobHandler.Get)
+				})
+				mux.Route("/v1/dead-letters", func(r chi.Router) {
+					r.Get("/", deadLetterHandler.List)
+					r.Get("/stats", deadLetterHandler.Stats)
+					r.Get("/export", deadLetterHandler.Export)
+					r.Get("/{delivery_id}", deadLetterHandler.Get)
+					r.Post("/{delivery_id}/replay", deadLetterHandler.Replay)
+					r.Post("/{delivery_id}/discard", deadLetterHandler.Discard)
+				})
 				mux.Get("/swagger/*", httpSwagger.Handler(
 					httpSwagger.URL("/swagger/doc.json"), //The url pointing to API definition"
 				))
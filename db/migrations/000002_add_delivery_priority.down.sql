@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS deliveries_dispatch_idx;
+ALTER TABLE deliveries DROP COLUMN IF EXISTS priority;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS priority;
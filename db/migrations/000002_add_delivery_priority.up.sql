@@ -0,0 +1,9 @@
+-- webhooks table
+
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS priority SMALLINT NOT NULL DEFAULT 0;
+
+-- deliveries table
+
+ALTER TABLE deliveries ADD COLUMN IF NOT EXISTS priority SMALLINT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS deliveries_dispatch_idx ON deliveries (priority DESC, created_at ASC) WHERE status = 'pending';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS webhooks_queue_idx;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS queue;
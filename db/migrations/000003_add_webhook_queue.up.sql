@@ -0,0 +1,5 @@
+-- webhooks table
+
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS queue VARCHAR NOT NULL DEFAULT 'default';
+
+CREATE INDEX IF NOT EXISTS webhooks_queue_idx ON webhooks (queue);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS deliveries_expires_at_idx;
+ALTER TABLE deliveries DROP COLUMN IF EXISTS expires_at;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS delivery_ttl;
@@ -0,0 +1,9 @@
+-- webhooks table
+
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS delivery_ttl INTEGER NOT NULL DEFAULT 0;
+
+-- deliveries table
+
+ALTER TABLE deliveries ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS deliveries_expires_at_idx ON deliveries (expires_at) WHERE status = 'pending';
@@ -0,0 +1,15 @@
+-- replay_jobs table
+
+CREATE TABLE IF NOT EXISTS replay_jobs(
+   id UUID PRIMARY KEY,
+   filters JSONB NOT NULL,
+   reset_delivery_attempts BOOLEAN NOT NULL,
+   status VARCHAR NOT NULL,
+   replayed_deliveries INTEGER NOT NULL,
+   error TEXT NOT NULL,
+   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+   updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS replay_jobs_status_idx ON replay_jobs (status);
+CREATE INDEX IF NOT EXISTS replay_jobs_created_at_idx ON replay_jobs USING BRIN(created_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS delivery_attempts_kind_idx;
+ALTER TABLE delivery_attempts DROP COLUMN IF EXISTS url;
+ALTER TABLE delivery_attempts DROP COLUMN IF EXISTS kind;
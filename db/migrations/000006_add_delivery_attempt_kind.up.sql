@@ -0,0 +1,6 @@
+-- delivery_attempts table
+
+ALTER TABLE delivery_attempts ADD COLUMN IF NOT EXISTS kind VARCHAR NOT NULL DEFAULT 'dispatch';
+ALTER TABLE delivery_attempts ADD COLUMN IF NOT EXISTS url VARCHAR NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS delivery_attempts_kind_idx ON delivery_attempts (kind);
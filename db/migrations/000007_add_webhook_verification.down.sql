@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS webhooks_verification_status_idx;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS verified_at;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS verification_token;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS verification_status;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS require_verification;
@@ -0,0 +1,8 @@
+-- webhooks table
+
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS require_verification BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS verification_status VARCHAR NOT NULL DEFAULT 'not_required';
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS verification_token VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS verified_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS webhooks_verification_status_idx ON webhooks (verification_status);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS deliveries_idempotency_key_idx;
+ALTER TABLE deliveries DROP COLUMN IF EXISTS idempotency_key;
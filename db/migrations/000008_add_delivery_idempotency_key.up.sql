@@ -0,0 +1,5 @@
+-- deliveries table
+
+ALTER TABLE deliveries ADD COLUMN IF NOT EXISTS idempotency_key VARCHAR NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS deliveries_idempotency_key_idx ON deliveries (webhook_id, idempotency_key) WHERE idempotency_key <> '';
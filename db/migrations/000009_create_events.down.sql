@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS deliveries_event_id_idx;
+ALTER TABLE deliveries DROP COLUMN IF EXISTS event_id;
+DROP INDEX IF EXISTS webhooks_event_types_idx;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS event_types;
+DROP TABLE IF EXISTS events;
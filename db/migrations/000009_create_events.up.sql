@@ -0,0 +1,24 @@
+-- events table
+
+CREATE TABLE IF NOT EXISTS events(
+   id UUID PRIMARY KEY,
+   event_type VARCHAR NOT NULL,
+   payload TEXT NOT NULL,
+   deliveries INTEGER NOT NULL,
+   created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS events_event_type_idx ON events (event_type);
+CREATE INDEX IF NOT EXISTS events_created_at_idx ON events USING BRIN(created_at);
+
+-- webhooks table
+
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS event_types VARCHAR[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS webhooks_event_types_idx ON webhooks USING GIN(event_types);
+
+-- deliveries table
+
+ALTER TABLE deliveries ADD COLUMN IF NOT EXISTS event_id UUID REFERENCES events (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS deliveries_event_id_idx ON deliveries (event_id);
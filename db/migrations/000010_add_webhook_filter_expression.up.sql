@@ -0,0 +1,3 @@
+-- webhooks table
+
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS filter_expression VARCHAR NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- webhooks table
+
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS payload_template TEXT NOT NULL DEFAULT '';
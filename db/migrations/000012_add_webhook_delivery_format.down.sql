@@ -0,0 +1,2 @@
+ALTER TABLE deliveries DROP COLUMN IF EXISTS event_type;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS delivery_format;
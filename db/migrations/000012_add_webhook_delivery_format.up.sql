@@ -0,0 +1,7 @@
+-- webhooks table
+
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS delivery_format VARCHAR NOT NULL DEFAULT 'raw';
+
+-- deliveries table
+
+ALTER TABLE deliveries ADD COLUMN IF NOT EXISTS event_type VARCHAR NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS postmand_try_jsonb(TEXT);
+DROP INDEX IF EXISTS deliveries_payload_json_idx;
+ALTER TABLE deliveries DROP COLUMN IF EXISTS payload_json;
@@ -0,0 +1,16 @@
+-- deliveries table
+
+ALTER TABLE deliveries ADD COLUMN IF NOT EXISTS payload_json JSONB;
+
+CREATE INDEX IF NOT EXISTS deliveries_payload_json_idx ON deliveries USING GIN (payload_json jsonb_path_ops);
+
+-- Used by the backfill-payload-json command, the payloads of the existing deliveries are not converted here
+-- because a single update of the whole table would lock the deliveries for a long time
+
+CREATE OR REPLACE FUNCTION postmand_try_jsonb(value TEXT) RETURNS JSONB AS $$
+BEGIN
+    RETURN value::JSONB;
+EXCEPTION WHEN others THEN
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
@@ -0,0 +1,4 @@
+-- deliveries table
+
+ALTER TABLE deliveries ADD COLUMN IF NOT EXISTS payload_encoding VARCHAR NOT NULL DEFAULT '';
+ALTER TABLE deliveries ADD COLUMN IF NOT EXISTS payload_bytes BYTEA;
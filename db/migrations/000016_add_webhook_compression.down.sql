@@ -0,0 +1,2 @@
+ALTER TABLE delivery_attempts DROP COLUMN IF EXISTS compression;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS compression;
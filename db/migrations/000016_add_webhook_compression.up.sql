@@ -0,0 +1,7 @@
+-- webhooks table
+
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS compression VARCHAR NOT NULL DEFAULT 'none';
+
+-- delivery_attempts table
+
+ALTER TABLE delivery_attempts ADD COLUMN IF NOT EXISTS compression VARCHAR NOT NULL DEFAULT 'none';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS delivery_attempts_batch_id_idx;
+ALTER TABLE delivery_attempts DROP COLUMN IF EXISTS batch_id;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS batch_format;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS max_batch_wait;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS max_batch_size;
@@ -0,0 +1,10 @@
+-- webhooks table
+
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS max_batch_size INT NOT NULL DEFAULT 0;
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS max_batch_wait INT NOT NULL DEFAULT 0;
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS batch_format VARCHAR NOT NULL DEFAULT 'json_array';
+
+-- delivery_attempts table
+
+ALTER TABLE delivery_attempts ADD COLUMN IF NOT EXISTS batch_id UUID;
+CREATE INDEX IF NOT EXISTS delivery_attempts_batch_id_idx ON delivery_attempts (batch_id);
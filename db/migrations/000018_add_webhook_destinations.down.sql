@@ -0,0 +1,2 @@
+ALTER TABLE webhooks DROP COLUMN IF EXISTS destination_strategy;
+ALTER TABLE webhooks DROP COLUMN IF EXISTS destinations;
@@ -0,0 +1,2 @@
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS destinations VARCHAR[] NOT NULL DEFAULT '{}';
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS destination_strategy VARCHAR NOT NULL DEFAULT 'failover';
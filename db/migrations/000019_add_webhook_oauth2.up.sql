@@ -0,0 +1 @@
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS oauth2 JSONB;
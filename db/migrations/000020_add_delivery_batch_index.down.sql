@@ -0,0 +1 @@
+DROP INDEX IF EXISTS deliveries_batch_idx;
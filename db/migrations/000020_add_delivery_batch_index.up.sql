@@ -0,0 +1,3 @@
+-- deliveries table
+
+CREATE INDEX IF NOT EXISTS deliveries_batch_idx ON deliveries (webhook_id, status, scheduled_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS webhook_destination_cursors;
+ALTER TABLE deliveries DROP COLUMN IF EXISTS destination_index;
@@ -0,0 +1,11 @@
+-- deliveries table
+
+ALTER TABLE deliveries ADD COLUMN IF NOT EXISTS destination_index INT NOT NULL DEFAULT 0;
+
+-- webhook_destination_cursors table
+
+CREATE TABLE IF NOT EXISTS webhook_destination_cursors(
+   webhook_id UUID PRIMARY KEY,
+   next_position BIGINT NOT NULL,
+   FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+);
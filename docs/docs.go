@@ -16,7 +16,7 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/deliveries": {
+        "/dead-letters": {
             "get": {
                 "consumes": [
                     "application/json"
@@ -25,9 +25,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "deliveries"
+                    "dead-letters"
                 ],
-                "summary": "List deliveries",
+                "summary": "List deliveries that failed after all delivery attempts",
                 "parameters": [
                     {
                         "type": "integer",
@@ -47,12 +47,6 @@ const docTemplate = `{
                         "name": "webhook_id",
                         "in": "query"
                     },
-                    {
-                        "type": "string",
-                        "description": "Filter by status field",
-                        "name": "status",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
                         "description": "Return results where the created_at field is greater than this value",
@@ -82,7 +76,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/DeliveryList"
+                            "$ref": "#/definitions/DeadLetterList"
                         }
                     },
                     "500": {
@@ -92,40 +86,54 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/dead-letters/export": {
+            "get": {
                 "produces": [
-                    "application/json"
+                    "application/x-ndjson"
                 ],
                 "tags": [
-                    "deliveries"
+                    "dead-letters"
                 ],
-                "summary": "Add an delivery",
+                "summary": "Export the dead-lettered deliveries as newline delimited json",
                 "parameters": [
                     {
-                        "description": "Add delivery",
-                        "name": "delivery",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/Delivery"
-                        }
+                        "type": "string",
+                        "description": "Filter by webhook_id field",
+                        "name": "webhook_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is greater than this value",
+                        "name": "created_at.gt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is greater than or equal to this value",
+                        "name": "created_at.gte",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is less than this value",
+                        "name": "created_at.lt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is less than or equal to this value",
+                        "name": "created_at.lte",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/Delivery"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Error"
+                            "$ref": "#/definitions/DeadLetter"
                         }
                     },
                     "500": {
@@ -137,7 +145,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/deliveries/{delivery_id}": {
+        "/dead-letters/stats": {
             "get": {
                 "consumes": [
                     "application/json"
@@ -146,29 +154,58 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "deliveries"
+                    "dead-letters"
                 ],
-                "summary": "Show a delivery",
+                "summary": "Count the dead-lettered deliveries per webhook",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The limit indicates the maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset indicates the starting position of the query in relation to the complete set of unpaginated items",
+                        "name": "offset",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Delivery ID",
-                        "name": "delivery_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Filter by webhook_id field",
+                        "name": "webhook_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is greater than this value",
+                        "name": "created_at.gt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is greater than or equal to this value",
+                        "name": "created_at.gte",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is less than this value",
+                        "name": "created_at.lt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is less than or equal to this value",
+                        "name": "created_at.lte",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Delivery"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/Error"
+                            "$ref": "#/definitions/DeadLetterStatsList"
                         }
                     },
                     "500": {
@@ -178,8 +215,10 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/dead-letters/{delivery_id}": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -187,9 +226,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "deliveries"
+                    "dead-letters"
                 ],
-                "summary": "Delete an delivery",
+                "summary": "Show a dead-lettered delivery",
                 "parameters": [
                     {
                         "type": "string",
@@ -200,8 +239,11 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/DeadLetter"
+                        }
                     },
                     "404": {
                         "description": "Not Found",
@@ -218,8 +260,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/delivery-attempts": {
-            "get": {
+        "/dead-letters/{delivery_id}/discard": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -227,70 +269,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "delivery-attempts"
+                    "dead-letters"
                 ],
-                "summary": "List delivery attempts",
+                "summary": "Discard a dead-lettered delivery",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "The limit indicates the maximum number of items to return",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "The offset indicates the starting position of the query in relation to the complete set of unpaginated items",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filter by webhook_id",
-                        "name": "webhook_id",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Filter by delivery_id",
+                        "description": "Delivery ID",
                         "name": "delivery_id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "boolean",
-                        "description": "Filter by success",
-                        "name": "success",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Return results where the created_at field is greater than this value",
-                        "name": "created_at.gt",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Return results where the created_at field is greater than or equal to this value",
-                        "name": "created_at.gte",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Return results where the created_at field is less than this value",
-                        "name": "created_at.lt",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Return results where the created_at field is less than or equal to this value",
-                        "name": "created_at.lte",
-                        "in": "query"
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/DeliveryAttemptList"
+                            "$ref": "#/definitions/Delivery"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
                         }
                     },
                     "500": {
@@ -302,8 +303,8 @@ const docTemplate = `{
                 }
             }
         },
-        "/delivery-attempts/{delivery_attempt_id}": {
-            "get": {
+        "/dead-letters/{delivery_id}/replay": {
+            "post": {
                 "consumes": [
                     "application/json"
                 ],
@@ -311,23 +312,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "delivery-attempts"
+                    "dead-letters"
                 ],
-                "summary": "Show a delivery attempt",
+                "summary": "Send again a dead-lettered delivery",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Delivery Attempt ID",
-                        "name": "delivery_attempt_id",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Replay options",
+                        "name": "delivery",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/DeliveryRetry"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/DeliveryAttempt"
+                            "$ref": "#/definitions/Delivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
                         }
                     },
                     "404": {
@@ -345,7 +360,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/webhooks": {
+        "/deliveries": {
             "get": {
                 "consumes": [
                     "application/json"
@@ -354,9 +369,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "webhooks"
+                    "deliveries"
                 ],
-                "summary": "List webhooks",
+                "summary": "List deliveries",
                 "parameters": [
                     {
                         "type": "integer",
@@ -371,9 +386,21 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "boolean",
-                        "description": "Filter by active field",
-                        "name": "active",
+                        "type": "string",
+                        "description": "Filter by webhook_id field",
+                        "name": "webhook_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by event_id field",
+                        "name": "event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status field",
+                        "name": "status",
                         "in": "query"
                     },
                     {
@@ -399,13 +426,19 @@ const docTemplate = `{
                         "description": "Return results where the created_at field is less than or equal to this value",
                         "name": "created_at.lte",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by a field of the JSON payload, eg: payload.order_id=1234 or payload.customer.id=1",
+                        "name": "payload.field",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/WebhookList"
+                            "$ref": "#/definitions/DeliveryList"
                         }
                     },
                     "500": {
@@ -424,25 +457,925 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "webhooks"
+                    "deliveries"
                 ],
-                "summary": "Add an webhook",
+                "summary": "Add an delivery",
                 "parameters": [
                     {
-                        "description": "Add webhook",
-                        "name": "webhook",
+                        "description": "Add delivery",
+                        "name": "delivery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/DeliveryCreate"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Idempotency key, the original delivery is returned when the key was already used by the webhook",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The original delivery of the idempotency key",
+                        "schema": {
+                            "$ref": "#/definitions/Delivery"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Delivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/deliveries/{delivery_id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "deliveries"
+                ],
+                "summary": "Show a delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Delivery"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "deliveries"
+                ],
+                "summary": "Delete an delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/deliveries/{delivery_id}/cancel": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "deliveries"
+                ],
+                "summary": "Cancel a pending delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Delivery"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/deliveries/{delivery_id}/replay-to-url": {
+            "post": {
+                "description": "The delivery is not changed, the result is recorded as a delivery attempt with the replay kind.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "deliveries"
+                ],
+                "summary": "Send the payload of a delivery to an alternate url",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Replay options",
+                        "name": "delivery",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/DeliveryReplayToURL"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/DeliveryAttempt"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/deliveries/{delivery_id}/retry": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "deliveries"
+                ],
+                "summary": "Retry a succeeded or failed delivery",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Retry options",
+                        "name": "delivery",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/DeliveryRetry"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Delivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery-attempts": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "delivery-attempts"
+                ],
+                "summary": "List delivery attempts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The limit indicates the maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset indicates the starting position of the query in relation to the complete set of unpaginated items",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by webhook_id",
+                        "name": "webhook_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by delivery_id",
+                        "name": "delivery_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by batch_id",
+                        "name": "batch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by success",
+                        "name": "success",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by kind (dispatch or replay)",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is greater than this value",
+                        "name": "created_at.gt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is greater than or equal to this value",
+                        "name": "created_at.gte",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is less than this value",
+                        "name": "created_at.lt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is less than or equal to this value",
+                        "name": "created_at.lte",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/DeliveryAttemptList"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/delivery-attempts/{delivery_attempt_id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "delivery-attempts"
+                ],
+                "summary": "Show a delivery attempt",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Delivery Attempt ID",
+                        "name": "delivery_attempt_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/DeliveryAttempt"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "List events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The limit indicates the maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset indicates the starting position of the query in relation to the complete set of unpaginated items",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by event_type field",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is greater than this value",
+                        "name": "created_at.gt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is greater than or equal to this value",
+                        "name": "created_at.gte",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is less than this value",
+                        "name": "created_at.lt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is less than or equal to this value",
+                        "name": "created_at.lte",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/EventList"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "One delivery is created for each active webhook subscribed to the event type, use the event_id filter to list them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Add an event",
+                "parameters": [
+                    {
+                        "description": "Add event",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Event"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{event_id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Show an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event ID",
+                        "name": "event_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Event"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/replay-jobs": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "replay-jobs"
+                ],
+                "summary": "List replay jobs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The limit indicates the maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset indicates the starting position of the query in relation to the complete set of unpaginated items",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by status field",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is greater than this value",
+                        "name": "created_at.gt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is greater than or equal to this value",
+                        "name": "created_at.gte",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is less than this value",
+                        "name": "created_at.lt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is less than or equal to this value",
+                        "name": "created_at.lte",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ReplayJobList"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "The replay job is executed in background by the workers and sends again the deliveries that match the filters.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "replay-jobs"
+                ],
+                "summary": "Add a replay job",
+                "parameters": [
+                    {
+                        "description": "Add replay job",
+                        "name": "replay_job",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ReplayJob"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/ReplayJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/replay-jobs/{replay_job_id}": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "replay-jobs"
+                ],
+                "summary": "Show a replay job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Replay Job ID",
+                        "name": "replay_job_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ReplayJob"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "The limit indicates the maximum number of items to return",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "The offset indicates the starting position of the query in relation to the complete set of unpaginated items",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by active field",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by queue field",
+                        "name": "queue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is greater than this value",
+                        "name": "created_at.gt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is greater than or equal to this value",
+                        "name": "created_at.gte",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is less than this value",
+                        "name": "created_at.lt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Return results where the created_at field is less than or equal to this value",
+                        "name": "created_at.lte",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/WebhookList"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Add an webhook",
+                "parameters": [
+                    {
+                        "description": "Add webhook",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/Webhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/filter-dry-run": {
+            "post": {
+                "description": "Nothing is sent, use it to check which payloads a webhook with the filter expression receives.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Evaluate a filter expression against a sample payload",
+                "parameters": [
+                    {
+                        "description": "Filter expression and sample payload",
+                        "name": "filter_dry_run",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/Webhook"
+                            "$ref": "#/definitions/WebhookFilterDryRun"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Webhook"
+                            "$ref": "#/definitions/WebhookFilterDryRun"
                         }
                     },
                     "400": {
@@ -596,9 +1529,284 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "/webhooks/{webhook_id}/preview": {
+            "post": {
+                "description": "Nothing is sent, the sample payload is used if the body does not define one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Render a payload through the payload template of an webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Preview options",
+                        "name": "webhook",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/WebhookTest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/WebhookPreview"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{webhook_id}/test": {
+            "post": {
+                "description": "The payload is sent synchronously and no delivery is created, the sample payload is used if the body does not define one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Send a test payload to an webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Test options",
+                        "name": "webhook",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/WebhookTest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/WebhookTestResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{webhook_id}/verify": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Send again the verification challenge to an webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Webhook"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Error"
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
+        "DeadLetter": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "delivery_attempts": {
+                    "type": "integer"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "idempotency_key": {
+                    "type": "string"
+                },
+                "last_attempt_at": {
+                    "type": "string"
+                },
+                "last_attempt_error": {
+                    "type": "string"
+                },
+                "last_attempt_response_status_code": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "payload_encoding": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "scheduled_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "DeadLetterList": {
+            "type": "object",
+            "properties": {
+                "dead_letters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DeadLetter"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "DeadLetterStats": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "integer"
+                },
+                "last_failed_at": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "DeadLetterStatsList": {
+            "type": "object",
+            "properties": {
+                "dead_letter_stats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/DeadLetterStats"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
         "Delivery": {
             "type": "object",
             "properties": {
@@ -608,12 +1816,30 @@ const docTemplate = `{
                 "delivery_attempts": {
                     "type": "integer"
                 },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
+                "idempotency_key": {
+                    "type": "string"
+                },
                 "payload": {
                     "type": "string"
                 },
+                "payload_encoding": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
                 "scheduled_at": {
                     "type": "string"
                 },
@@ -631,6 +1857,12 @@ const docTemplate = `{
         "DeliveryAttempt": {
             "type": "object",
             "properties": {
+                "batch_id": {
+                    "type": "string"
+                },
+                "compression": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
@@ -646,6 +1878,9 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "kind": {
+                    "type": "string"
+                },
                 "raw_request": {
                     "type": "string"
                 },
@@ -658,6 +1893,9 @@ const docTemplate = `{
                 "success": {
                     "type": "boolean"
                 },
+                "url": {
+                    "type": "string"
+                },
                 "webhook_id": {
                     "type": "string"
                 }
@@ -680,6 +1918,56 @@ const docTemplate = `{
                 }
             }
         },
+        "DeliveryCreate": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "delay": {
+                    "type": "integer"
+                },
+                "delivery_attempts": {
+                    "type": "integer"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "idempotency_key": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "payload_encoding": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "scheduled_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
         "DeliveryList": {
             "type": "object",
             "properties": {
@@ -697,6 +1985,22 @@ const docTemplate = `{
                 }
             }
         },
+        "DeliveryReplayToURL": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "DeliveryRetry": {
+            "type": "object",
+            "properties": {
+                "reset_delivery_attempts": {
+                    "type": "boolean"
+                }
+            }
+        },
         "Error": {
             "type": "object",
             "properties": {
@@ -711,12 +2015,101 @@ const docTemplate = `{
                 }
             }
         },
+        "Event": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "deliveries": {
+                    "type": "integer"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                }
+            }
+        },
+        "EventList": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Event"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ReplayJob": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "filters": {
+                    "$ref": "#/definitions/postmand.ReplayJobFilters"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "replayed_deliveries": {
+                    "type": "integer"
+                },
+                "reset_delivery_attempts": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "ReplayJobList": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "replay_jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ReplayJob"
+                    }
+                }
+            }
+        },
         "Webhook": {
             "type": "object",
             "properties": {
                 "active": {
                     "type": "boolean"
                 },
+                "batch_format": {
+                    "type": "string"
+                },
+                "compression": {
+                    "type": "string"
+                },
                 "content_type": {
                     "type": "string"
                 },
@@ -726,15 +2119,63 @@ const docTemplate = `{
                 "delivery_attempt_timeout": {
                     "type": "integer"
                 },
+                "delivery_format": {
+                    "type": "string"
+                },
+                "delivery_ttl": {
+                    "type": "integer"
+                },
+                "destination_strategy": {
+                    "type": "string"
+                },
+                "destinations": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "filter_expression": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
+                "max_batch_size": {
+                    "type": "integer"
+                },
+                "max_batch_wait": {
+                    "type": "integer"
+                },
                 "max_delivery_attempts": {
                     "type": "integer"
                 },
                 "name": {
                     "type": "string"
                 },
+                "oauth2": {
+                    "$ref": "#/definitions/WebhookOAuth2"
+                },
+                "payload_schema": {
+                    "type": "string"
+                },
+                "payload_template": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "queue": {
+                    "type": "string"
+                },
+                "require_verification": {
+                    "type": "boolean"
+                },
                 "retry_max_backoff": {
                     "type": "integer"
                 },
@@ -755,6 +2196,26 @@ const docTemplate = `{
                     "items": {
                         "type": "integer"
                     }
+                },
+                "verification_status": {
+                    "type": "string"
+                },
+                "verified_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "WebhookFilterDryRun": {
+            "type": "object",
+            "properties": {
+                "filter_expression": {
+                    "type": "string"
+                },
+                "matched": {
+                    "type": "boolean"
+                },
+                "payload": {
+                    "type": "string"
                 }
             }
         },
@@ -775,6 +2236,83 @@ const docTemplate = `{
                 }
             }
         },
+        "WebhookOAuth2": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "client_secret": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "WebhookPreview": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "rendered_payload": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "WebhookTest": {
+            "type": "object",
+            "properties": {
+                "payload": {
+                    "type": "string"
+                }
+            }
+        },
+        "WebhookTestResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "execution_duration": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "raw_request": {
+                    "type": "string"
+                },
+                "raw_response": {
+                    "type": "string"
+                },
+                "response_status_code": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
         "handler.errorResponseCode": {
             "type": "integer",
             "enum": [
@@ -784,7 +2322,14 @@ const docTemplate = `{
                 4,
                 5,
                 6,
-                7
+                7,
+                8,
+                9,
+                10,
+                11,
+                12,
+                13,
+                14
             ],
             "x-enum-varnames": [
                 "internalServerErrorCode",
@@ -793,8 +2338,21 @@ const docTemplate = `{
                 "requestValidationFailedCode",
                 "webhookNotFoundCode",
                 "deliveryNotFoundCode",
-                "deliveryAttemptNotFoundCode"
+                "deliveryAttemptNotFoundCode",
+                "deliveryNotCancellableCode",
+                "deliveryNotRetryableCode",
+                "replayJobNotFoundCode",
+                "deadLetterNotFoundCode",
+                "webhookVerificationFailedCode",
+                "eventNotFoundCode",
+                "webhookNotVerifiedCode"
             ]
+        },
+        "postmand.ReplayJobFilters": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
         }
     }
 }`
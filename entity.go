@@ -1,6 +1,12 @@
 package postmand
 
 import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -16,6 +22,85 @@ const (
 	DeliveryStatusSucceeded = "succeeded"
 	// DeliveryStatusFailed represents the delivery failed status
 	DeliveryStatusFailed = "failed"
+	// DeliveryStatusExpired represents the delivery expired status
+	DeliveryStatusExpired = "expired"
+	// DeliveryStatusCancelled represents the delivery cancelled status
+	DeliveryStatusCancelled = "cancelled"
+	// DeliveryStatusDiscarded represents the status of a failed delivery removed from the dead-letter queue
+	DeliveryStatusDiscarded = "discarded"
+	// DeliveryPriorityMin represents the lowest delivery priority
+	DeliveryPriorityMin = 0
+	// DeliveryPriorityMax represents the highest delivery priority
+	DeliveryPriorityMax = 100
+	// DeliveryScheduleTolerance represents how much a delivery scheduled_at can be in the past
+	DeliveryScheduleTolerance = 5 * time.Minute
+	// DeliveryScheduleMaxDelay represents how much a delivery scheduled_at can be in the future
+	DeliveryScheduleMaxDelay = 30 * 24 * time.Hour
+	// DeliveryIdempotencyKeyMaxLength represents the max length of a delivery idempotency key
+	DeliveryIdempotencyKeyMaxLength = 255
+	// DeliveryIdempotencyKeyRetention represents the default time window an idempotency key returns the original delivery
+	DeliveryIdempotencyKeyRetention = 24 * time.Hour
+	// DeliveryPayloadMaxSize represents the default max size of a delivery payload (in bytes)
+	DeliveryPayloadMaxSize = 1024 * 1024
+	// DeliveryPayloadEncodingBase64 represents a binary payload sent as a base64 string, it is dispatched as raw bytes
+	DeliveryPayloadEncodingBase64 = "base64"
+	// DeliveryAttemptKindDispatch represents an attempt made by the workers to the webhook url
+	DeliveryAttemptKindDispatch = "dispatch"
+	// DeliveryAttemptKindReplay represents an attempt made to an alternate url that does not change the delivery
+	DeliveryAttemptKindReplay = "replay"
+	// ReplayJobStatusPending represents the replay job pending status
+	ReplayJobStatusPending = "pending"
+	// ReplayJobStatusCompleted represents the replay job completed status
+	ReplayJobStatusCompleted = "completed"
+	// ReplayJobStatusFailed represents the replay job failed status
+	ReplayJobStatusFailed = "failed"
+	// WebhookQueueDefault represents the queue used when the webhook does not define one
+	WebhookQueueDefault = "default"
+	// WebhookVerificationStatusNotRequired represents a webhook that does not require the url verification
+	WebhookVerificationStatusNotRequired = "not_required"
+	// WebhookVerificationStatusPending represents a webhook that is waiting the url verification, it is not dispatched
+	WebhookVerificationStatusPending = "pending_verification"
+	// WebhookVerificationStatusVerified represents a webhook that has the url verified
+	WebhookVerificationStatusVerified = "verified"
+	// WebhookDeliveryFormatRaw represents a webhook that receives the payload as is
+	WebhookDeliveryFormatRaw = "raw"
+	// WebhookDeliveryFormatCloudEventsStructured represents a webhook that receives the payload wrapped in a CloudEvents envelope
+	WebhookDeliveryFormatCloudEventsStructured = "cloudevents_structured"
+	// WebhookDeliveryFormatCloudEventsBinary represents a webhook that receives the payload with the CloudEvents attributes as ce-* headers
+	WebhookDeliveryFormatCloudEventsBinary = "cloudevents_binary"
+	// WebhookCompressionNone represents a webhook that receives the request body uncompressed
+	WebhookCompressionNone = "none"
+	// WebhookCompressionGzip represents a webhook that receives the request body compressed with gzip
+	WebhookCompressionGzip = "gzip"
+	// WebhookCompressionDeflate represents a webhook that receives the request body compressed with deflate (zlib format)
+	WebhookCompressionDeflate = "deflate"
+	// WebhookBatchFormatJSONArray represents a webhook that receives the batched deliveries as a JSON array
+	WebhookBatchFormatJSONArray = "json_array"
+	// WebhookBatchFormatNDJSON represents a webhook that receives the batched deliveries as newline delimited JSON
+	WebhookBatchFormatNDJSON = "ndjson"
+	// WebhookMaxBatchSizeLimit represents the max amount of deliveries sent in a single request
+	WebhookMaxBatchSizeLimit = 1000
+	// WebhookDestinationStrategyFailover represents a webhook that moves a delivery to the next destination after a failed request
+	WebhookDestinationStrategyFailover = "failover"
+	// WebhookDestinationStrategyRoundRobin represents a webhook that sends each delivery attempt to the next destination
+	WebhookDestinationStrategyRoundRobin = "round_robin"
+	// WebhookDestinationStrategyRandom represents a webhook that sends each delivery attempt to a random destination
+	WebhookDestinationStrategyRandom = "random"
+	// WebhookMaxDestinations represents the max amount of additional destinations of a webhook
+	WebhookMaxDestinations = 10
+	// WebhookTestPayload represents the payload sent by the webhook test when the request does not define one
+	WebhookTestPayload = `{"event": "postmand.test"}`
+)
+
+var (
+	webhookQueueRegex = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+	eventTypeRegex    = regexp.MustCompile(`^[a-zA-Z0-9_-]+(\.[a-zA-Z0-9_-]+)*$`)
+	// eventTypePatternRegex accepts an event type or a prefix followed by a wildcard (order.*) or only a wildcard (*).
+	eventTypePatternRegex = regexp.MustCompile(`^([a-zA-Z0-9_-]+\.)*([a-zA-Z0-9_-]+|\*)$`)
+	// oauth2ScopeRegex matches the scope tokens of RFC 6749, section 3.3.
+	oauth2ScopeRegex = regexp.MustCompile(`^[\x21\x23-\x5b\x5d-\x7e]+$`)
+	// ReplayJobFilterKeys contains the delivery filters accepted by replay jobs.
+	ReplayJobFilterKeys = []string{"webhook_id", "status", "created_at.gt", "created_at.gte", "created_at.lt", "created_at.lte"}
 )
 
 // ID represents the primary key for all entities.
@@ -23,59 +108,254 @@ type ID = uuid.UUID
 
 // Webhook represents a webhook in the system.
 type Webhook struct {
-	ID                     ID            `json:"id" db:"id"`
-	Name                   string        `json:"name" db:"name"`
-	URL                    string        `json:"url" db:"url"`
-	ContentType            string        `json:"content_type" db:"content_type"`
-	ValidStatusCodes       pq.Int32Array `json:"valid_status_codes" db:"valid_status_codes"`
-	SecretToken            string        `json:"secret_token" db:"secret_token"`
-	Active                 bool          `json:"active" db:"active"`
-	MaxDeliveryAttempts    int           `json:"max_delivery_attempts" db:"max_delivery_attempts"`
-	DeliveryAttemptTimeout int           `json:"delivery_attempt_timeout" db:"delivery_attempt_timeout"`
-	RetryMinBackoff        int           `json:"retry_min_backoff" db:"retry_min_backoff"`
-	RetryMaxBackoff        int           `json:"retry_max_backoff" db:"retry_max_backoff"`
-	CreatedAt              time.Time     `json:"created_at" db:"created_at"`
-	UpdatedAt              time.Time     `json:"updated_at" db:"updated_at"`
+	ID                     ID             `json:"id" db:"id"`
+	Name                   string         `json:"name" db:"name"`
+	URL                    string         `json:"url" db:"url"`
+	ContentType            string         `json:"content_type" db:"content_type"`
+	ValidStatusCodes       pq.Int32Array  `json:"valid_status_codes" db:"valid_status_codes"`
+	SecretToken            string         `json:"secret_token" db:"secret_token"`
+	Active                 bool           `json:"active" db:"active"`
+	MaxDeliveryAttempts    int            `json:"max_delivery_attempts" db:"max_delivery_attempts"`
+	DeliveryAttemptTimeout int            `json:"delivery_attempt_timeout" db:"delivery_attempt_timeout"`
+	RetryMinBackoff        int            `json:"retry_min_backoff" db:"retry_min_backoff"`
+	RetryMaxBackoff        int            `json:"retry_max_backoff" db:"retry_max_backoff"`
+	Priority               int            `json:"priority" db:"priority"`
+	Queue                  string         `json:"queue" db:"queue"`
+	DeliveryTTL            int            `json:"delivery_ttl" db:"delivery_ttl"`
+	EventTypes             pq.StringArray `json:"event_types" db:"event_types"`
+	FilterExpression       string         `json:"filter_expression" db:"filter_expression"`
+	PayloadTemplate        string         `json:"payload_template" db:"payload_template"`
+	PayloadSchema          string         `json:"payload_schema" db:"payload_schema"`
+	DeliveryFormat         string         `json:"delivery_format" db:"delivery_format"`
+	Compression            string         `json:"compression" db:"compression"`
+	MaxBatchSize           int            `json:"max_batch_size" db:"max_batch_size"`
+	MaxBatchWait           int            `json:"max_batch_wait" db:"max_batch_wait"`
+	BatchFormat            string         `json:"batch_format" db:"batch_format"`
+	Destinations           pq.StringArray `json:"destinations" db:"destinations"`
+	DestinationStrategy    string         `json:"destination_strategy" db:"destination_strategy"`
+	OAuth2                 *WebhookOAuth2 `json:"oauth2" db:"oauth2"`
+	RequireVerification    bool           `json:"require_verification" db:"require_verification"`
+	VerificationStatus     string         `json:"verification_status" db:"verification_status"`
+	VerificationToken      string         `json:"-" db:"verification_token"`
+	VerifiedAt             *time.Time     `json:"verified_at" db:"verified_at"`
+	CreatedAt              time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt              time.Time      `json:"updated_at" db:"updated_at"`
 } //@name Webhook
 
+// WebhookOAuth2 represents the OAuth2 client credentials used to obtain the bearer token sent to the webhook.
+type WebhookOAuth2 struct {
+	TokenURL     string   `json:"token_url"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	Scopes       []string `json:"scopes"`
+} //@name WebhookOAuth2
+
+// Value implements driver.Valuer interface.
+func (o WebhookOAuth2) Value() (driver.Value, error) {
+	return json.Marshal(o)
+}
+
+// Scan implements sql.Scanner interface.
+func (o *WebhookOAuth2) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, o)
+	case string:
+		return json.Unmarshal([]byte(v), o)
+	}
+	return fmt.Errorf("unsupported type for webhook oauth2: %T", value)
+}
+
+// Validate implements ozzo validation Validatable interface
+func (o WebhookOAuth2) Validate() error {
+	return validation.ValidateStruct(&o,
+		validation.Field(&o.TokenURL, validation.Required, is.URL),
+		validation.Field(&o.ClientID, validation.Required, validation.Length(1, 255)),
+		validation.Field(&o.ClientSecret, validation.Required, validation.Length(1, 255)),
+		validation.Field(&o.Scopes, validation.Each(validation.Required, validation.Match(oauth2ScopeRegex))),
+	)
+}
+
+// DestinationURLs returns the url followed by the additional destinations.
+func (w Webhook) DestinationURLs() []string {
+	return append([]string{w.URL}, w.Destinations...)
+}
+
+func (w Webhook) hasURLPlaceholders() bool {
+	for _, url := range w.DestinationURLs() {
+		if HasURLPlaceholders(url) {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate implements ozzo validation Validatable interface
 func (w Webhook) Validate() error {
 	return validation.ValidateStruct(&w,
 		validation.Field(&w.Name, validation.Required, validation.Length(3, 255)),
-		validation.Field(&w.URL, validation.Required, is.URL),
+		validation.Field(&w.URL, validation.Required, validation.By(validateWebhookURL)),
 		validation.Field(&w.ContentType, validation.Required),
 		validation.Field(&w.ValidStatusCodes, validation.Required),
 		validation.Field(&w.MaxDeliveryAttempts, validation.Required, validation.Min(1)),
 		validation.Field(&w.DeliveryAttemptTimeout, validation.Required, validation.Min(1)),
 		validation.Field(&w.RetryMinBackoff, validation.Required, validation.Min(1)),
 		validation.Field(&w.RetryMaxBackoff, validation.Required, validation.Min(1)),
+		validation.Field(&w.Priority, validation.Min(DeliveryPriorityMin), validation.Max(DeliveryPriorityMax)),
+		validation.Field(&w.Queue, validation.Length(1, 255), validation.Match(webhookQueueRegex)),
+		validation.Field(&w.DeliveryTTL, validation.Min(0)),
+		validation.Field(&w.EventTypes, validation.Each(validation.Required, validation.Match(eventTypePatternRegex))),
+		validation.Field(&w.FilterExpression, validation.Length(0, 1024), validation.By(validateFilterExpression)),
+		validation.Field(&w.PayloadTemplate, validation.Length(0, 65536), validation.By(validatePayloadTemplate)),
+		validation.Field(&w.PayloadSchema, validation.Length(0, 65536), validation.By(validatePayloadSchema)),
+		validation.Field(
+			&w.DeliveryFormat,
+			validation.In(WebhookDeliveryFormatRaw, WebhookDeliveryFormatCloudEventsStructured, WebhookDeliveryFormatCloudEventsBinary),
+		),
+		validation.Field(&w.Compression, validation.In(WebhookCompressionNone, WebhookCompressionGzip, WebhookCompressionDeflate)),
+		validation.Field(
+			&w.MaxBatchSize,
+			validation.Min(0),
+			validation.Max(WebhookMaxBatchSizeLimit),
+			validation.When(
+				w.DeliveryFormat == WebhookDeliveryFormatCloudEventsBinary,
+				validation.Max(1).Error("must be no greater than 1 when delivery_format is cloudevents_binary"),
+			),
+		),
+		validation.Field(&w.MaxBatchWait, validation.Min(0)),
+		validation.Field(
+			&w.Destinations,
+			// pq.StringArray is a driver.Valuer, the length rule would be applied to the array literal
+			validation.By(func(value interface{}) error {
+				return validation.Validate([]string(w.Destinations), validation.Length(0, WebhookMaxDestinations))
+			}),
+			validation.Each(validation.Required, validation.By(validateWebhookURL)),
+		),
+		validation.Field(
+			&w.DestinationStrategy,
+			validation.In(WebhookDestinationStrategyFailover, WebhookDestinationStrategyRoundRobin, WebhookDestinationStrategyRandom),
+		),
+		validation.Field(&w.OAuth2),
+		validation.Field(
+			&w.RequireVerification,
+			validation.When(w.hasURLPlaceholders(), validation.Empty.Error("must be false when the url has placeholders")),
+		),
+		validation.Field(&w.BatchFormat, validation.In(WebhookBatchFormatJSONArray, WebhookBatchFormatNDJSON)),
+	)
+}
+
+// WebhookPreview represents a payload rendered through the webhook payload template without sending it.
+type WebhookPreview struct {
+	WebhookID       ID     `json:"webhook_id"`
+	Payload         string `json:"payload"`
+	RenderedPayload string `json:"rendered_payload"`
+	Error           string `json:"error"`
+} //@name WebhookPreview
+
+// WebhookFilterDryRun represents a filter expression evaluated against a sample payload.
+type WebhookFilterDryRun struct {
+	FilterExpression string `json:"filter_expression"`
+	Payload          string `json:"payload"`
+	Matched          bool   `json:"matched"`
+} //@name WebhookFilterDryRun
+
+// Validate implements ozzo validation Validatable interface
+func (w WebhookFilterDryRun) Validate() error {
+	return validation.ValidateStruct(&w,
+		validation.Field(&w.FilterExpression, validation.Required, validation.Length(0, 1024), validation.By(validateFilterExpression)),
+		validation.Field(&w.Payload, validation.Required, is.JSON),
 	)
 }
 
+// WebhookTestResult represents the result of a payload sent to a webhook without creating a delivery.
+type WebhookTestResult struct {
+	WebhookID          ID     `json:"webhook_id"`
+	URL                string `json:"url"`
+	Payload            string `json:"payload"`
+	RawRequest         string `json:"raw_request"`
+	RawResponse        string `json:"raw_response"`
+	ResponseStatusCode int    `json:"response_status_code"`
+	ExecutionDuration  int    `json:"execution_duration"`
+	Success            bool   `json:"success"`
+	Error              string `json:"error"`
+} //@name WebhookTestResult
+
 // Delivery represents a payload that must be delivery using webhook context.
 type Delivery struct {
-	ID               ID        `json:"id" db:"id"`
-	WebhookID        ID        `json:"webhook_id" db:"webhook_id"`
-	Payload          string    `json:"payload" db:"payload"`
-	ScheduledAt      time.Time `json:"scheduled_at" db:"scheduled_at"`
-	DeliveryAttempts int       `json:"delivery_attempts" db:"delivery_attempts"`
-	Status           string    `json:"status" db:"status"`
-	CreatedAt        time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+	ID               ID         `json:"id" db:"id"`
+	WebhookID        ID         `json:"webhook_id" db:"webhook_id"`
+	Payload          string     `json:"payload" db:"payload"`
+	PayloadEncoding  string     `json:"payload_encoding,omitempty" db:"payload_encoding"`
+	PayloadJSON      *string    `json:"-" db:"payload_json"`
+	PayloadBytes     []byte     `json:"-" db:"payload_bytes"`
+	DestinationIndex int        `json:"-" db:"destination_index"`
+	ScheduledAt      time.Time  `json:"scheduled_at" db:"scheduled_at"`
+	DeliveryAttempts int        `json:"delivery_attempts" db:"delivery_attempts"`
+	Status           string     `json:"status" db:"status"`
+	Priority         int        `json:"priority" db:"priority"`
+	ExpiresAt        *time.Time `json:"expires_at" db:"expires_at"`
+	IdempotencyKey   string     `json:"idempotency_key,omitempty" db:"idempotency_key"`
+	EventID          *ID        `json:"event_id,omitempty" db:"event_id"`
+	EventType        string     `json:"event_type,omitempty" db:"event_type"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
 } //@name Delivery
 
+// Body returns the bytes sent to the webhook, a base64 payload is decoded.
+func (d Delivery) Body() ([]byte, error) {
+	if d.PayloadEncoding == DeliveryPayloadEncodingBase64 {
+		return base64.StdEncoding.DecodeString(d.Payload)
+	}
+	return []byte(d.Payload), nil
+}
+
 // Validate implements ozzo validation Validatable interface
 func (d Delivery) Validate() error {
+	now := time.Now().UTC()
 	return validation.ValidateStruct(&d,
 		validation.Field(&d.WebhookID, validation.Required, is.UUIDv4),
+		validation.Field(&d.Payload, validation.When(d.PayloadEncoding == DeliveryPayloadEncodingBase64, is.Base64)),
+		validation.Field(&d.PayloadEncoding, validation.In(DeliveryPayloadEncodingBase64)),
+		validation.Field(&d.Priority, validation.Min(DeliveryPriorityMin), validation.Max(DeliveryPriorityMax)),
+		validation.Field(
+			&d.ExpiresAt,
+			validation.Min(now),
+			validation.When(!d.ScheduledAt.IsZero(), validation.Min(d.ScheduledAt).Exclusive().Error("must be after scheduled_at")),
+		),
+		validation.Field(&d.IdempotencyKey, validation.Length(1, DeliveryIdempotencyKeyMaxLength)),
+		validation.Field(&d.EventType, validation.Length(1, 255), validation.Match(eventTypeRegex)),
+		validation.Field(
+			&d.ScheduledAt,
+			validation.Min(now.Add(-DeliveryScheduleTolerance)),
+			validation.Max(now.Add(DeliveryScheduleMaxDelay)),
+		),
 	)
 }
 
+// DeadLetter represents a delivery that failed after all delivery attempts, with the result of its last attempt.
+type DeadLetter struct {
+	Delivery
+	LastAttemptError              string     `json:"last_attempt_error" db:"last_attempt_error"`
+	LastAttemptResponseStatusCode int        `json:"last_attempt_response_status_code" db:"last_attempt_response_status_code"`
+	LastAttemptAt                 *time.Time `json:"last_attempt_at" db:"last_attempt_at"`
+} //@name DeadLetter
+
+// DeadLetterStats represents the amount of dead-lettered deliveries of a webhook.
+type DeadLetterStats struct {
+	WebhookID    ID        `json:"webhook_id" db:"webhook_id"`
+	Deliveries   int       `json:"deliveries" db:"deliveries"`
+	LastFailedAt time.Time `json:"last_failed_at" db:"last_failed_at"`
+} //@name DeadLetterStats
+
 // DeliveryAttempt represents a delivery attempt.
 type DeliveryAttempt struct {
 	ID                 ID        `json:"id" db:"id"`
 	WebhookID          ID        `json:"webhook_id" db:"webhook_id"`
 	DeliveryID         ID        `json:"delivery_id" db:"delivery_id"`
+	Kind               string    `json:"kind" db:"kind"`
+	URL                string    `json:"url" db:"url"`
+	BatchID            *ID       `json:"batch_id,omitempty" db:"batch_id"`
+	Compression        string    `json:"compression" db:"compression"`
 	RawRequest         string    `json:"raw_request" db:"raw_request"`
 	RawResponse        string    `json:"raw_response" db:"raw_response"`
 	ResponseStatusCode int       `json:"response_status_code" db:"response_status_code"`
@@ -84,3 +364,103 @@ type DeliveryAttempt struct {
 	Error              string    `json:"error" db:"error"`
 	CreatedAt          time.Time `json:"created_at" db:"created_at"`
 } //@name DeliveryAttempt
+
+// ReplayJobFilters represents the delivery filters used by a replay job.
+type ReplayJobFilters map[string]string
+
+// Value implements driver.Valuer interface.
+func (f ReplayJobFilters) Value() (driver.Value, error) {
+	return json.Marshal(f)
+}
+
+// Scan implements sql.Scanner interface.
+func (f *ReplayJobFilters) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, f)
+	case string:
+		return json.Unmarshal([]byte(v), f)
+	}
+	return fmt.Errorf("unsupported type for replay job filters: %T", value)
+}
+
+// Validate implements ozzo validation Validatable interface
+func (f ReplayJobFilters) Validate() error {
+	errs := validation.Errors{}
+	for key, value := range f {
+		valid := false
+		for _, filterKey := range ReplayJobFilterKeys {
+			if key == filterKey {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("invalid filter %q", key)
+		}
+		if err := validation.Validate(value, replayJobFilterRules(key)...); err != nil {
+			errs[key] = err
+		}
+	}
+	return errs.Filter()
+}
+
+// replayJobFilterRules returns the rules of a replay job filter value, the value is used as is on the deliveries query.
+func replayJobFilterRules(key string) []validation.Rule {
+	switch {
+	case key == "webhook_id":
+		return []validation.Rule{validation.Required, is.UUID}
+	case key == "status":
+		// Only the succeeded and failed deliveries are requeued
+		return []validation.Rule{validation.Required, validation.In(DeliveryStatusSucceeded, DeliveryStatusFailed)}
+	case strings.HasPrefix(key, "created_at."):
+		return []validation.Rule{validation.Required, validation.Date(time.RFC3339)}
+	}
+	return nil
+}
+
+// Event represents a payload of an event type that is sent to every active webhook subscribed to the event type.
+type Event struct {
+	ID         ID        `json:"id" db:"id"`
+	EventType  string    `json:"event_type" db:"event_type"`
+	Payload    string    `json:"payload" db:"payload"`
+	Deliveries int       `json:"deliveries" db:"deliveries"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+} //@name Event
+
+// Validate implements ozzo validation Validatable interface
+func (e Event) Validate() error {
+	return validation.ValidateStruct(&e,
+		validation.Field(&e.EventType, validation.Required, validation.Length(1, 255), validation.Match(eventTypeRegex)),
+	)
+}
+
+// EventTypePatterns returns the webhook event types that match the event type, the event type itself,
+// each of its prefixes followed by a wildcard and the wildcard alone (order.created matches order.created, order.* and *).
+func EventTypePatterns(eventType string) []string {
+	patterns := []string{"*"}
+	segments := strings.Split(eventType, ".")
+	for i := 1; i < len(segments); i++ {
+		patterns = append(patterns, strings.Join(segments[:i], ".")+".*")
+	}
+	return append(patterns, eventType)
+}
+
+// ReplayJob represents a background job that sends again the deliveries that match the filters.
+type ReplayJob struct {
+	ID                    ID               `json:"id" db:"id"`
+	Filters               ReplayJobFilters `json:"filters" db:"filters"`
+	ResetDeliveryAttempts bool             `json:"reset_delivery_attempts" db:"reset_delivery_attempts"`
+	Status                string           `json:"status" db:"status"`
+	ReplayedDeliveries    int              `json:"replayed_deliveries" db:"replayed_deliveries"`
+	Error                 string           `json:"error" db:"error"`
+	CreatedAt             time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time        `json:"updated_at" db:"updated_at"`
+} //@name ReplayJob
+
+// Validate implements ozzo validation Validatable interface
+func (r ReplayJob) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Filters, validation.Required),
+	)
+}
@@ -33,6 +33,86 @@ func TestWebhook(t *testing.T) {
 			Webhook{ID: uuid.New(), Name: strings.Repeat("A", 300), URL: "https://httpbin.org/post", ContentType: "application/json", ValidStatusCodes: pq.Int32Array{200, 201}, MaxDeliveryAttempts: 1, DeliveryAttemptTimeout: 1, RetryMinBackoff: 1, RetryMaxBackoff: 1},
 			`{"name":"the length must be between 3 and 255"}`,
 		},
+		{
+			"Invalid priority",
+			Webhook{ID: uuid.New(), Name: "AAA", URL: "https://httpbin.org/post", ContentType: "application/json", ValidStatusCodes: pq.Int32Array{200, 201}, MaxDeliveryAttempts: 1, DeliveryAttemptTimeout: 1, RetryMinBackoff: 1, RetryMaxBackoff: 1, Priority: 101},
+			`{"priority":"must be no greater than 100"}`,
+		},
+		{
+			"Invalid queue",
+			Webhook{ID: uuid.New(), Name: "AAA", URL: "https://httpbin.org/post", ContentType: "application/json", ValidStatusCodes: pq.Int32Array{200, 201}, MaxDeliveryAttempts: 1, DeliveryAttemptTimeout: 1, RetryMinBackoff: 1, RetryMaxBackoff: 1, Queue: "tenant 1"},
+			`{"queue":"must be in a valid format"}`,
+		},
+		{
+			"Invalid event types",
+			Webhook{ID: uuid.New(), Name: "AAA", URL: "https://httpbin.org/post", ContentType: "application/json", ValidStatusCodes: pq.Int32Array{200, 201}, MaxDeliveryAttempts: 1, DeliveryAttemptTimeout: 1, RetryMinBackoff: 1, RetryMaxBackoff: 1, EventTypes: pq.StringArray{"order.*", "order.*.created"}},
+			`{"event_types":{"1":"must be in a valid format"}}`,
+		},
+		{
+			"Invalid filter expression",
+			Webhook{ID: uuid.New(), Name: "AAA", URL: "https://httpbin.org/post", ContentType: "application/json", ValidStatusCodes: pq.Int32Array{200, 201}, MaxDeliveryAttempts: 1, DeliveryAttemptTimeout: 1, RetryMinBackoff: 1, RetryMaxBackoff: 1, FilterExpression: `payload.country ==`},
+			`{"filter_expression":"unexpected end of expression"}`,
+		},
+		{
+			"Invalid payload template",
+			Webhook{ID: uuid.New(), Name: "AAA", URL: "https://httpbin.org/post", ContentType: "application/json", ValidStatusCodes: pq.Int32Array{200, 201}, MaxDeliveryAttempts: 1, DeliveryAttemptTimeout: 1, RetryMinBackoff: 1, RetryMaxBackoff: 1, PayloadTemplate: `{"text": {{ .message }`},
+			`{"payload_template":"template: payload_template:1: unexpected \"}\" in operand"}`,
+		},
+		{
+			"Invalid payload schema",
+			Webhook{ID: uuid.New(), Name: "AAA", URL: "https://httpbin.org/post", ContentType: "application/json", ValidStatusCodes: pq.Int32Array{200, 201}, MaxDeliveryAttempts: 1, DeliveryAttemptTimeout: 1, RetryMinBackoff: 1, RetryMaxBackoff: 1, PayloadSchema: `{"type": "decimal"}`},
+			`{"payload_schema":"schema: unsupported type \"decimal\""}`,
+		},
+		{
+			"Invalid delivery format",
+			Webhook{ID: uuid.New(), Name: "AAA", URL: "https://httpbin.org/post", ContentType: "application/json", ValidStatusCodes: pq.Int32Array{200, 201}, MaxDeliveryAttempts: 1, DeliveryAttemptTimeout: 1, RetryMinBackoff: 1, RetryMaxBackoff: 1, DeliveryFormat: "cloudevents"},
+			`{"delivery_format":"must be a valid value"}`,
+		},
+		{
+			"Invalid compression",
+			Webhook{ID: uuid.New(), Name: "AAA", URL: "https://httpbin.org/post", ContentType: "application/json", ValidStatusCodes: pq.Int32Array{200, 201}, MaxDeliveryAttempts: 1, DeliveryAttemptTimeout: 1, RetryMinBackoff: 1, RetryMaxBackoff: 1, Compression: "br"},
+			`{"compression":"must be a valid value"}`,
+		},
+		{
+			"Invalid batch mode",
+			Webhook{ID: uuid.New(), Name: "AAA", URL: "https://httpbin.org/post", ContentType: "application/json", ValidStatusCodes: pq.Int32Array{200, 201}, MaxDeliveryAttempts: 1, DeliveryAttemptTimeout: 1, RetryMinBackoff: 1, RetryMaxBackoff: 1, MaxBatchSize: 1001, MaxBatchWait: -1, BatchFormat: "csv"},
+			`{"batch_format":"must be a valid value","max_batch_size":"must be no greater than 1000","max_batch_wait":"must be no less than 0"}`,
+		},
+		{
+			"Invalid batch size with cloudevents binary",
+			Webhook{ID: uuid.New(), Name: "AAA", URL: "https://httpbin.org/post", ContentType: "application/json", ValidStatusCodes: pq.Int32Array{200, 201}, MaxDeliveryAttempts: 1, DeliveryAttemptTimeout: 1, RetryMinBackoff: 1, RetryMaxBackoff: 1, DeliveryFormat: "cloudevents_binary", MaxBatchSize: 10},
+			`{"max_batch_size":"must be no greater than 1 when delivery_format is cloudevents_binary"}`,
+		},
+		{
+			"Invalid url placeholder",
+			Webhook{ID: uuid.New(), Name: "AAA", URL: "https://httpbin.org/{tenant-id}/post", ContentType: "application/json", ValidStatusCodes: pq.Int32Array{200, 201}, MaxDeliveryAttempts: 1, DeliveryAttemptTimeout: 1, RetryMinBackoff: 1, RetryMaxBackoff: 1},
+			`{"url":"invalid placeholder \"{tenant-id}\""}`,
+		},
+		{
+			"Host url placeholder",
+			Webhook{ID: uuid.New(), Name: "AAA", URL: "https://{tenant}.httpbin.org/post", ContentType: "application/json", ValidStatusCodes: pq.Int32Array{200, 201}, MaxDeliveryAttempts: 1, DeliveryAttemptTimeout: 1, RetryMinBackoff: 1, RetryMaxBackoff: 1},
+			`{"url":"placeholder \"{tenant}\" is only allowed in the url path or query"}`,
+		},
+		{
+			"Require verification with url placeholders",
+			Webhook{ID: uuid.New(), Name: "AAA", URL: "https://httpbin.org/{tenant}/post", ContentType: "application/json", ValidStatusCodes: pq.Int32Array{200, 201}, MaxDeliveryAttempts: 1, DeliveryAttemptTimeout: 1, RetryMinBackoff: 1, RetryMaxBackoff: 1, RequireVerification: true},
+			`{"require_verification":"must be false when the url has placeholders"}`,
+		},
+		{
+			"Invalid destinations",
+			Webhook{ID: uuid.New(), Name: "AAA", URL: "https://httpbin.org/post", ContentType: "application/json", ValidStatusCodes: pq.Int32Array{200, 201}, MaxDeliveryAttempts: 1, DeliveryAttemptTimeout: 1, RetryMinBackoff: 1, RetryMaxBackoff: 1, Destinations: pq.StringArray{"https://httpbin.org/anything", "httpbin"}, DestinationStrategy: "weighted"},
+			`{"destination_strategy":"must be a valid value","destinations":{"1":"must be a valid URL"}}`,
+		},
+		{
+			"Invalid oauth2",
+			Webhook{ID: uuid.New(), Name: "AAA", URL: "https://httpbin.org/post", ContentType: "application/json", ValidStatusCodes: pq.Int32Array{200, 201}, MaxDeliveryAttempts: 1, DeliveryAttemptTimeout: 1, RetryMinBackoff: 1, RetryMaxBackoff: 1, OAuth2: &WebhookOAuth2{TokenURL: "token", ClientID: "client", Scopes: []string{"read", "write all"}}},
+			`{"oauth2":{"client_secret":"cannot be blank","scopes":{"1":"must be in a valid format"},"token_url":"must be a valid URL"}}`,
+		},
+		{
+			"Require verification with destination placeholders",
+			Webhook{ID: uuid.New(), Name: "AAA", URL: "https://httpbin.org/post", ContentType: "application/json", ValidStatusCodes: pq.Int32Array{200, 201}, MaxDeliveryAttempts: 1, DeliveryAttemptTimeout: 1, RetryMinBackoff: 1, RetryMaxBackoff: 1, Destinations: pq.StringArray{"https://httpbin.org/{region}/post"}, RequireVerification: true},
+			`{"require_verification":"must be false when the url has placeholders"}`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.kind, func(t *testing.T) {
@@ -70,6 +150,21 @@ func TestDelivery(t *testing.T) {
 			Delivery{},
 			`{"webhook_id":"must be a valid UUID v4"}`,
 		},
+		{
+			"Invalid priority",
+			Delivery{WebhookID: uuid.New(), Priority: -1},
+			`{"priority":"must be no less than 0"}`,
+		},
+		{
+			"Invalid payload encoding",
+			Delivery{WebhookID: uuid.New(), Payload: "AQID", PayloadEncoding: "hex"},
+			`{"payload_encoding":"must be a valid value"}`,
+		},
+		{
+			"Invalid base64 payload",
+			Delivery{WebhookID: uuid.New(), Payload: "{", PayloadEncoding: DeliveryPayloadEncodingBase64},
+			`{"payload":"must be encoded in Base64"}`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.kind, func(t *testing.T) {
@@ -92,4 +187,121 @@ func TestDelivery(t *testing.T) {
 	}
 	err := delivery.Validate()
 	assert.Nil(t, err)
+
+	delivery.Payload = "AAECAw=="
+	delivery.PayloadEncoding = DeliveryPayloadEncodingBase64
+	err = delivery.Validate()
+	assert.Nil(t, err)
+	body, err := delivery.Body()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{0, 1, 2, 3}, body)
+
+	expiresAt := time.Now().UTC().Add(-time.Minute)
+	delivery.ExpiresAt = &expiresAt
+	err = delivery.Validate()
+	assert.Contains(t, err.Error(), "expires_at: must be no less than")
+
+	delivery.ScheduledAt = time.Now().UTC().Add(time.Hour)
+	expiresAt = delivery.ScheduledAt
+	delivery.ExpiresAt = &expiresAt
+	err = delivery.Validate()
+	assert.Contains(t, err.Error(), "expires_at: must be after scheduled_at")
+
+	expiresAt = delivery.ScheduledAt.Add(time.Minute)
+	delivery.ExpiresAt = &expiresAt
+	err = delivery.Validate()
+	assert.Nil(t, err)
+
+	delivery.ExpiresAt = nil
+	delivery.ScheduledAt = time.Now().UTC().Add(-time.Hour)
+	err = delivery.Validate()
+	assert.Contains(t, err.Error(), "scheduled_at: must be no less than")
+
+	delivery.ScheduledAt = time.Now().UTC().Add(DeliveryScheduleMaxDelay + time.Hour)
+	err = delivery.Validate()
+	assert.Contains(t, err.Error(), "scheduled_at: must be no greater than")
+}
+
+func TestReplayJob(t *testing.T) {
+	var tests = []struct {
+		kind            string
+		request         ReplayJob
+		expectedPayload string
+	}{
+		{
+			"required fields",
+			ReplayJob{},
+			`{"filters":"cannot be blank"}`,
+		},
+		{
+			"Invalid filter",
+			ReplayJob{Filters: ReplayJobFilters{"payload": "{}"}},
+			`{"filters":"invalid filter \"payload\""}`,
+		},
+		{
+			"Invalid filter values",
+			ReplayJob{Filters: ReplayJobFilters{"webhook_id": "x", "status": "done", "created_at.gt": "2021-01-01", "created_at.lte": ""}},
+			`{"filters":{"created_at.gt":"must be a valid date","created_at.lte":"cannot be blank","status":"must be a valid value","webhook_id":"must be a valid UUID"}}`,
+		},
+		{
+			"Status filter not requeued",
+			ReplayJob{Filters: ReplayJobFilters{"status": DeliveryStatusPending}},
+			`{"filters":{"status":"must be a valid value"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			err := tt.request.Validate()
+			assert.NotNil(t, err)
+			errorPayload, err := json.Marshal(err)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedPayload, string(errorPayload))
+		})
+	}
+
+	replayJob := ReplayJob{Filters: ReplayJobFilters{"webhook_id": uuid.New().String(), "status": DeliveryStatusFailed, "created_at.gte": "2021-01-01T00:00:00Z"}}
+	err := replayJob.Validate()
+	assert.Nil(t, err)
+
+	replayJob.Filters["status"] = DeliveryStatusSucceeded
+	err = replayJob.Validate()
+	assert.Nil(t, err)
+}
+
+func TestEvent(t *testing.T) {
+	var tests = []struct {
+		kind            string
+		request         Event
+		expectedPayload string
+	}{
+		{
+			"required fields",
+			Event{},
+			`{"event_type":"cannot be blank"}`,
+		},
+		{
+			"Invalid event type",
+			Event{EventType: "order.*"},
+			`{"event_type":"must be in a valid format"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			err := tt.request.Validate()
+			assert.NotNil(t, err)
+			errorPayload, err := json.Marshal(err)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedPayload, string(errorPayload))
+		})
+	}
+
+	event := Event{EventType: "order.created", Payload: `{"id": 1}`}
+	err := event.Validate()
+	assert.Nil(t, err)
+}
+
+func TestEventTypePatterns(t *testing.T) {
+	assert.Equal(t, []string{"*", "order"}, EventTypePatterns("order"))
+	assert.Equal(t, []string{"*", "order.*", "order.created"}, EventTypePatterns("order.created"))
+	assert.Equal(t, []string{"*", "order.*", "order.item.*", "order.item.created"}, EventTypePatterns("order.item.created"))
 }
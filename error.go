@@ -9,4 +9,20 @@ var (
 	ErrDeliveryNotFound = errors.New("delivery_not_found")
 	// ErrDeliveryAttemptNotFound is returned by any operation that can't load a delivery attempt.
 	ErrDeliveryAttemptNotFound = errors.New("delivery_attempt_not_found")
+	// ErrReplayJobNotFound is returned by any operation that can't load a replay job.
+	ErrReplayJobNotFound = errors.New("replay_job_not_found")
+	// ErrDeadLetterNotFound is returned by any operation that can't load a dead-lettered delivery.
+	ErrDeadLetterNotFound = errors.New("dead_letter_not_found")
+	// ErrWebhookVerificationFailed is returned when the webhook url does not answer the verification challenge.
+	ErrWebhookVerificationFailed = errors.New("webhook_verification_failed")
+	// ErrDeliveryNotCancellable is returned when a delivery that is not pending is cancelled.
+	ErrDeliveryNotCancellable = errors.New("delivery_not_cancellable")
+	// ErrDeliveryNotRetryable is returned when a delivery that is not succeeded or failed is retried.
+	ErrDeliveryNotRetryable = errors.New("delivery_not_retryable")
+	// ErrDeliveryIdempotencyKeyConflict is returned when a delivery is created with an idempotency key already used by the webhook.
+	ErrDeliveryIdempotencyKeyConflict = errors.New("delivery_idempotency_key_conflict")
+	// ErrEventNotFound is returned by any operation that can't load an event.
+	ErrEventNotFound = errors.New("event_not_found")
+	// ErrWebhookNotVerified is returned when a payload is sent to a webhook that is pending verification.
+	ErrWebhookNotVerified = errors.New("webhook_not_verified")
 )
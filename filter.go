@@ -0,0 +1,463 @@
+package postmand
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// filterExpressionRoot is the identifier that references the JSON payload in filter expressions.
+const filterExpressionRoot = "payload"
+
+type filterTokenKind int
+
+const (
+	filterTokenEOF filterTokenKind = iota
+	filterTokenIdent
+	filterTokenNumber
+	filterTokenString
+	filterTokenOperator
+)
+
+type filterToken struct {
+	kind     filterTokenKind
+	value    string
+	position int
+}
+
+func (t filterToken) String() string {
+	if t.kind == filterTokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q at position %d", t.value, t.position)
+}
+
+// filterOperators are sorted by length, the longest operator is matched first.
+var filterOperators = []string{"==", "!=", ">=", "<=", "&&", "||", ">", "<", "!", "(", ")", ".", "[", "]"}
+
+func tokenizeFilterExpression(expression string) ([]filterToken, error) {
+	tokens := []filterToken{}
+	i := 0
+	for i < len(expression) {
+		c, size := utf8.DecodeRuneInString(expression[i:])
+		switch {
+		case unicode.IsSpace(c):
+			i += size
+		case isFilterIdentStart(c):
+			start := i
+			for i < len(expression) {
+				r, size := utf8.DecodeRuneInString(expression[i:])
+				if !isFilterIdentStart(r) && !unicode.IsDigit(r) {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenIdent, value: expression[start:i], position: start})
+		case isASCIIDigit(c) || (c == '-' && i+1 < len(expression) && isASCIIDigit(rune(expression[i+1]))):
+			start := i
+			i++
+			for i < len(expression) && (isASCIIDigit(rune(expression[i])) || expression[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenNumber, value: expression[start:i], position: start})
+		case c == '"':
+			start := i
+			i++
+			for i < len(expression) && expression[i] != '"' {
+				if expression[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(expression) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			i++
+			value, err := strconv.Unquote(expression[start:i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at position %d", start)
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenString, value: value, position: start})
+		default:
+			matched := false
+			for _, operator := range filterOperators {
+				if strings.HasPrefix(expression[i:], operator) {
+					tokens = append(tokens, filterToken{kind: filterTokenOperator, value: operator, position: i})
+					i += len(operator)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected %q at position %d", string(c), i)
+			}
+		}
+	}
+	return append(tokens, filterToken{kind: filterTokenEOF, position: len(expression)}), nil
+}
+
+// isFilterIdentStart reports whether r starts an identifier, the identifiers accept unicode letters (payload.país).
+func isFilterIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// isASCIIDigit reports whether r is a digit accepted by strconv.ParseFloat.
+func isASCIIDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+type filterNode interface {
+	evaluate(payload interface{}) bool
+}
+
+type filterOperand interface {
+	resolve(payload interface{}) interface{}
+}
+
+type filterLiteral struct {
+	value interface{}
+}
+
+func (l filterLiteral) resolve(payload interface{}) interface{} {
+	return l.value
+}
+
+// filterPath contains the keys (string) and indexes (int) after the payload identifier.
+type filterPath struct {
+	segments []interface{}
+}
+
+func (p filterPath) resolve(payload interface{}) interface{} {
+	value := payload
+	for _, segment := range p.segments {
+		switch key := segment.(type) {
+		case string:
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			value = object[key]
+		case int:
+			array, ok := value.([]interface{})
+			if !ok || key < 0 || key >= len(array) {
+				return nil
+			}
+			value = array[key]
+		}
+	}
+	return value
+}
+
+type filterOr struct {
+	left, right filterNode
+}
+
+func (n filterOr) evaluate(payload interface{}) bool {
+	return n.left.evaluate(payload) || n.right.evaluate(payload)
+}
+
+type filterAnd struct {
+	left, right filterNode
+}
+
+func (n filterAnd) evaluate(payload interface{}) bool {
+	return n.left.evaluate(payload) && n.right.evaluate(payload)
+}
+
+type filterNot struct {
+	node filterNode
+}
+
+func (n filterNot) evaluate(payload interface{}) bool {
+	return !n.node.evaluate(payload)
+}
+
+type filterComparison struct {
+	operator    string
+	left, right filterOperand
+}
+
+func (n filterComparison) evaluate(payload interface{}) bool {
+	left := n.left.resolve(payload)
+	right := n.right.resolve(payload)
+	switch n.operator {
+	case "==":
+		return reflect.DeepEqual(left, right)
+	case "!=":
+		return !reflect.DeepEqual(left, right)
+	}
+
+	// Ordering operators only compare numbers with numbers and strings with strings
+	var compare int
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return false
+		}
+		switch {
+		case l < r:
+			compare = -1
+		case l > r:
+			compare = 1
+		}
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return false
+		}
+		compare = strings.Compare(l, r)
+	default:
+		return false
+	}
+	switch n.operator {
+	case ">":
+		return compare > 0
+	case ">=":
+		return compare >= 0
+	case "<":
+		return compare < 0
+	default:
+		return compare <= 0
+	}
+}
+
+// filterTruthy is used when an operand is not compared, null, false, 0 and empty values are false.
+type filterTruthy struct {
+	operand filterOperand
+}
+
+func (n filterTruthy) evaluate(payload interface{}) bool {
+	switch value := n.operand.resolve(payload).(type) {
+	case nil:
+		return false
+	case bool:
+		return value
+	case float64:
+		return value != 0
+	case string:
+		return value != ""
+	case []interface{}:
+		return len(value) > 0
+	case map[string]interface{}:
+		return len(value) > 0
+	default:
+		return true
+	}
+}
+
+type filterParser struct {
+	tokens   []filterToken
+	position int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.position]
+}
+
+func (p *filterParser) next() filterToken {
+	token := p.tokens[p.position]
+	if token.kind != filterTokenEOF {
+		p.position++
+	}
+	return token
+}
+
+func (p *filterParser) isOperator(values ...string) bool {
+	token := p.peek()
+	if token.kind != filterTokenOperator {
+		return false
+	}
+	for _, value := range values {
+		if token.value == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *filterParser) expect(value string) error {
+	if !p.isOperator(value) {
+		return fmt.Errorf("expected %q, found %s", value, p.peek())
+	}
+	p.next()
+	return nil
+}
+
+// parseOr parses: and ("||" and)*
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = filterOr{left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseAnd parses: unary ("&&" unary)*
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("&&") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = filterAnd{left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseUnary parses: "!" unary | "(" or ")" | operand (comparison operand)?
+func (p *filterParser) parseUnary() (filterNode, error) {
+	if p.isOperator("!") {
+		p.next()
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return filterNot{node: node}, nil
+	}
+	if p.isOperator("(") {
+		p.next()
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOperator("==", "!=", ">", ">=", "<", "<=") {
+		return filterTruthy{operand: left}, nil
+	}
+	operator := p.next().value
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return filterComparison{operator: operator, left: left, right: right}, nil
+}
+
+// parseOperand parses: string | number | true | false | null | payload ("." ident | "[" (number | string) "]")*
+func (p *filterParser) parseOperand() (filterOperand, error) {
+	token := p.next()
+	switch token.kind {
+	case filterTokenString:
+		return filterLiteral{value: token.value}, nil
+	case filterTokenNumber:
+		value, err := strconv.ParseFloat(token.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %s", token)
+		}
+		return filterLiteral{value: value}, nil
+	case filterTokenIdent:
+		switch token.value {
+		case "true":
+			return filterLiteral{value: true}, nil
+		case "false":
+			return filterLiteral{value: false}, nil
+		case "null":
+			return filterLiteral{value: nil}, nil
+		case filterExpressionRoot:
+			return p.parsePath()
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s", token)
+}
+
+func (p *filterParser) parsePath() (filterOperand, error) {
+	path := filterPath{segments: []interface{}{}}
+	for p.isOperator(".", "[") {
+		if p.next().value == "." {
+			token := p.next()
+			if token.kind != filterTokenIdent {
+				return nil, fmt.Errorf("expected field name, found %s", token)
+			}
+			path.segments = append(path.segments, token.value)
+			continue
+		}
+		token := p.next()
+		switch token.kind {
+		case filterTokenString:
+			path.segments = append(path.segments, token.value)
+		case filterTokenNumber:
+			index, err := strconv.Atoi(token.value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid index %s", token)
+			}
+			path.segments = append(path.segments, index)
+		default:
+			return nil, fmt.Errorf("expected index or quoted field name, found %s", token)
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	}
+	return path, nil
+}
+
+// FilterExpression is a parsed webhook filter expression that is evaluated against a JSON payload,
+// eg: payload.country == "BR" && (payload.amount >= 100 || payload.items[0]["sku-id"] != null).
+type FilterExpression struct {
+	root filterNode
+}
+
+// Evaluate returns whether the decoded JSON payload matches the filter expression.
+func (f *FilterExpression) Evaluate(payload interface{}) bool {
+	return f.root.evaluate(payload)
+}
+
+// Match returns whether the JSON payload matches the filter expression.
+func (f *FilterExpression) Match(payload string) (bool, error) {
+	var decodedPayload interface{}
+	if err := json.Unmarshal([]byte(payload), &decodedPayload); err != nil {
+		return false, err
+	}
+	return f.Evaluate(decodedPayload), nil
+}
+
+// ParseFilterExpression returns a FilterExpression or an error describing the invalid part of the expression.
+func ParseFilterExpression(expression string) (*FilterExpression, error) {
+	tokens, err := tokenizeFilterExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	parser := filterParser{tokens: tokens}
+	root, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if token := parser.peek(); token.kind != filterTokenEOF {
+		return nil, fmt.Errorf("unexpected %s", token)
+	}
+	return &FilterExpression{root: root}, nil
+}
+
+func validateFilterExpression(value interface{}) error {
+	expression, _ := value.(string)
+	if expression == "" {
+		return nil
+	}
+	_, err := ParseFilterExpression(expression)
+	return err
+}
@@ -0,0 +1,71 @@
+package postmand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterExpression(t *testing.T) {
+	payload := `{"country": "BR", "amount": 150.5, "paid": true, "coupon": null, "tags": ["vip"], "items": [{"sku-id": "A1", "quantity": 2}], "país": "BR"}`
+	var tests = []struct {
+		expression string
+		matched    bool
+	}{
+		{`payload.country == "BR"`, true},
+		{`payload.country != "BR"`, false},
+		{`payload.amount >= 100 && payload.amount < 200`, true},
+		{`payload.amount > "100"`, false},
+		{`payload.country > "AR"`, true},
+		{`payload.paid`, true},
+		{`!payload.paid`, false},
+		{`payload.coupon == null`, true},
+		{`payload.missing == null`, true},
+		{`payload.missing`, false},
+		{`payload.tags[0] == "vip"`, true},
+		{`payload.tags[1] == "vip"`, false},
+		{`payload.items[0]["sku-id"] == "A1"`, true},
+		{`payload.items[0].quantity == 2`, true},
+		{`payload.country == "AR" || (payload.paid && payload.amount > 100)`, true},
+		{`payload.country == "AR" || payload.paid && payload.amount > 1000`, false},
+		{`payload.amount == -1`, false},
+		{`payload.país == "BR"`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			filterExpression, err := ParseFilterExpression(tt.expression)
+			assert.Nil(t, err)
+			matched, err := filterExpression.Match(payload)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.matched, matched)
+		})
+	}
+
+	filterExpression, err := ParseFilterExpression(`payload.country == "BR"`)
+	assert.Nil(t, err)
+	_, err = filterExpression.Match("not json")
+	assert.NotNil(t, err)
+}
+
+func TestParseFilterExpression(t *testing.T) {
+	var tests = []struct {
+		expression    string
+		expectedError string
+	}{
+		{`payload.country ==`, "unexpected end of expression"},
+		{`country == "BR"`, `unexpected "country" at position 0`},
+		{`payload.country == "BR`, "unterminated string at position 19"},
+		{`payload.country = "BR"`, `unexpected "=" at position 16`},
+		{`(payload.paid`, `expected ")", found end of expression`},
+		{`payload.items[true]`, `expected index or quoted field name, found "true" at position 14`},
+		{`payload.paid payload.country`, `unexpected "payload" at position 13`},
+		{`payload.país € "BR"`, `unexpected "€" at position 14`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			_, err := ParseFilterExpression(tt.expression)
+			assert.NotNil(t, err)
+			assert.Equal(t, tt.expectedError, err.Error())
+		})
+	}
+}
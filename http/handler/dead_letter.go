@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/allisson/postmand"
+)
+
+const deadLetterExportPageSize = 500
+
+var deadLetterFilters = []string{"webhook_id", "created_at.gt", "created_at.gte", "created_at.lt", "created_at.lte"}
+
+type deadLetterList struct {
+	DeadLetters []*postmand.DeadLetter `json:"dead_letters"`
+	Limit       int                    `json:"limit"`
+	Offset      int                    `json:"offset"`
+} //@name DeadLetterList
+
+type deadLetterStatsList struct {
+	DeadLetterStats []*postmand.DeadLetterStats `json:"dead_letter_stats"`
+	Limit           int                         `json:"limit"`
+	Offset          int                         `json:"offset"`
+} //@name DeadLetterStatsList
+
+// DeadLetter implements rest interface for dead-lettered deliveries.
+type DeadLetter struct {
+	deadLetterService postmand.DeadLetterService
+	logger            *zap.Logger
+}
+
+// List dead letters.
+// List godoc
+// @Summary List deliveries that failed after all delivery attempts
+// @Tags dead-letters
+// @Accept json
+// @Produce json
+// @Param limit query int false "The limit indicates the maximum number of items to return"
+// @Param offset query int false "The offset indicates the starting position of the query in relation to the complete set of unpaginated items"
+// @Param webhook_id query string false "Filter by webhook_id field"
+// @Param created_at.gt query string false "Return results where the created_at field is greater than this value"
+// @Param created_at.gte query string false "Return results where the created_at field is greater than or equal to this value"
+// @Param created_at.lt query string false "Return results where the created_at field is less than this value"
+// @Param created_at.lte query string false "Return results where the created_at field is less than or equal to this value"
+// @Success 200 {object} deadLetterList
+// @Failure 500 {object} errorResponse
+// @Router /dead-letters [get]
+func (d DeadLetter) List(w http.ResponseWriter, r *http.Request) {
+	listOptions := makeListOptions(r, deadLetterFilters)
+	listOptions.OrderBy = "created_at"
+	listOptions.Order = "desc"
+
+	// Call service
+	deadLetters, err := d.deadLetterService.List(r.Context(), listOptions)
+	if err != nil {
+		d.logger.Error(
+			"service-error",
+			zap.String("name", "DeadLetterService"),
+			zap.String("method", "List"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, d.logger)
+		return
+	}
+
+	// Return response
+	dl := deadLetterList{
+		DeadLetters: deadLetters,
+		Limit:       listOptions.Limit,
+		Offset:      listOptions.Offset,
+	}
+	makeJSONResponse(w, http.StatusOK, dl, d.logger)
+}
+
+// Stats of dead letters.
+// Stats godoc
+// @Summary Count the dead-lettered deliveries per webhook
+// @Tags dead-letters
+// @Accept json
+// @Produce json
+// @Param limit query int false "The limit indicates the maximum number of items to return"
+// @Param offset query int false "The offset indicates the starting position of the query in relation to the complete set of unpaginated items"
+// @Param webhook_id query string false "Filter by webhook_id field"
+// @Param created_at.gt query string false "Return results where the created_at field is greater than this value"
+// @Param created_at.gte query string false "Return results where the created_at field is greater than or equal to this value"
+// @Param created_at.lt query string false "Return results where the created_at field is less than this value"
+// @Param created_at.lte query string false "Return results where the created_at field is less than or equal to this value"
+// @Success 200 {object} deadLetterStatsList
+// @Failure 500 {object} errorResponse
+// @Router /dead-letters/stats [get]
+func (d DeadLetter) Stats(w http.ResponseWriter, r *http.Request) {
+	listOptions := makeListOptions(r, deadLetterFilters)
+
+	// Call service
+	deadLetterStats, err := d.deadLetterService.Stats(r.Context(), listOptions)
+	if err != nil {
+		d.logger.Error(
+			"service-error",
+			zap.String("name", "DeadLetterService"),
+			zap.String("method", "Stats"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, d.logger)
+		return
+	}
+
+	// Return response
+	dsl := deadLetterStatsList{
+		DeadLetterStats: deadLetterStats,
+		Limit:           listOptions.Limit,
+		Offset:          listOptions.Offset,
+	}
+	makeJSONResponse(w, http.StatusOK, dsl, d.logger)
+}
+
+// Export dead letters.
+// Export godoc
+// @Summary Export the dead-lettered deliveries as newline delimited json
+// @Tags dead-letters
+// @Produce application/x-ndjson
+// @Param webhook_id query string false "Filter by webhook_id field"
+// @Param created_at.gt query string false "Return results where the created_at field is greater than this value"
+// @Param created_at.gte query string false "Return results where the created_at field is greater than or equal to this value"
+// @Param created_at.lt query string false "Return results where the created_at field is less than this value"
+// @Param created_at.lte query string false "Return results where the created_at field is less than or equal to this value"
+// @Success 200 {object} postmand.DeadLetter
+// @Failure 500 {object} errorResponse
+// @Router /dead-letters/export [get]
+func (d DeadLetter) Export(w http.ResponseWriter, r *http.Request) {
+	listOptions := makeListOptions(r, deadLetterFilters)
+	listOptions.Limit = deadLetterExportPageSize
+	listOptions.Offset = 0
+	listOptions.OrderBy = "created_at"
+	listOptions.Order = "asc"
+
+	encoder := json.NewEncoder(w)
+	headerWritten := false
+	for {
+		// Call service
+		deadLetters, err := d.deadLetterService.List(r.Context(), listOptions)
+		if err != nil {
+			d.logger.Error(
+				"service-error",
+				zap.String("name", "DeadLetterService"),
+				zap.String("method", "List"),
+				zap.Error(err),
+			)
+			// The response can't be changed after the first page is sent
+			if !headerWritten {
+				er := errorResponses["internal_server_error"]
+				makeErrorResponse(w, &er, d.logger)
+			}
+			return
+		}
+
+		if !headerWritten {
+			w.Header().Set("Content-Type", "application/x-ndjson; charset=utf-8")
+			w.Header().Set("Content-Disposition", `attachment; filename="dead-letters.ndjson"`)
+			w.WriteHeader(http.StatusOK)
+			headerWritten = true
+		}
+
+		for _, deadLetter := range deadLetters {
+			if err := encoder.Encode(deadLetter); err != nil {
+				d.logger.Error("http-failed-to-write-response-body", zap.Error(err))
+				return
+			}
+		}
+
+		if len(deadLetters) < listOptions.Limit {
+			return
+		}
+		listOptions.Offset += listOptions.Limit
+	}
+}
+
+// Get dead letter.
+// Get godoc
+// @Summary Show a dead-lettered delivery
+// @Tags dead-letters
+// @Accept json
+// @Produce json
+// @Param delivery_id path string true "Delivery ID"
+// @Success 200 {object} postmand.DeadLetter
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /dead-letters/{delivery_id} [get]
+func (d DeadLetter) Get(w http.ResponseWriter, r *http.Request) {
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "delivery_id"))
+	if err != nil {
+		er := errorResponses["invalid_id"]
+		makeErrorResponse(w, &er, d.logger)
+		return
+	}
+
+	// Call service
+	getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": deliveryID}}
+	deadLetter, err := d.deadLetterService.Get(r.Context(), getOptions)
+	if err != nil {
+		if err == postmand.ErrDeadLetterNotFound {
+			er := errorResponses["dead_letter_not_found"]
+			makeErrorResponse(w, &er, d.logger)
+			return
+		}
+		d.logger.Error(
+			"service-error",
+			zap.String("name", "DeadLetterService"),
+			zap.String("method", "Get"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, d.logger)
+		return
+	}
+
+	// Return response
+	makeJSONResponse(w, http.StatusOK, deadLetter, d.logger)
+}
+
+// Replay dead letter.
+// Replay godoc
+// @Summary Send again a dead-lettered delivery
+// @Tags dead-letters
+// @Accept json
+// @Produce json
+// @Param delivery_id path string true "Delivery ID"
+// @Param delivery body deliveryRetry false "Replay options"
+// @Success 200 {object} postmand.Delivery
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /dead-letters/{delivery_id}/replay [post]
+func (d DeadLetter) Replay(w http.ResponseWriter, r *http.Request) {
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "delivery_id"))
+	if err != nil {
+		er := errorResponses["invalid_id"]
+		makeErrorResponse(w, &er, d.logger)
+		return
+	}
+
+	// Parse request (the body is optional)
+	dr := deliveryRetry{}
+	if r.ContentLength > 0 {
+		if er := readBodyJSON(r, &dr, d.logger); er != nil {
+			makeErrorResponse(w, er, d.logger)
+			return
+		}
+	}
+
+	// Call service
+	delivery, err := d.deadLetterService.Replay(r.Context(), deliveryID, dr.ResetDeliveryAttempts)
+	if err != nil {
+		if err == postmand.ErrDeadLetterNotFound {
+			er := errorResponses["dead_letter_not_found"]
+			makeErrorResponse(w, &er, d.logger)
+			return
+		}
+		d.logger.Error(
+			"service-error",
+			zap.String("name", "DeadLetterService"),
+			zap.String("method", "Replay"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, d.logger)
+		return
+	}
+
+	// Return response
+	makeJSONResponse(w, http.StatusOK, delivery, d.logger)
+}
+
+// Discard dead letter.
+// Discard godoc
+// @Summary Discard a dead-lettered delivery
+// @Tags dead-letters
+// @Accept json
+// @Produce json
+// @Param delivery_id path string true "Delivery ID"
+// @Success 200 {object} postmand.Delivery
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /dead-letters/{delivery_id}/discard [post]
+func (d DeadLetter) Discard(w http.ResponseWriter, r *http.Request) {
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "delivery_id"))
+	if err != nil {
+		er := errorResponses["invalid_id"]
+		makeErrorResponse(w, &er, d.logger)
+		return
+	}
+
+	// Call service
+	delivery, err := d.deadLetterService.Discard(r.Context(), deliveryID)
+	if err != nil {
+		if err == postmand.ErrDeadLetterNotFound {
+			er := errorResponses["dead_letter_not_found"]
+			makeErrorResponse(w, &er, d.logger)
+			return
+		}
+		d.logger.Error(
+			"service-error",
+			zap.String("name", "DeadLetterService"),
+			zap.String("method", "Discard"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, d.logger)
+		return
+	}
+
+	// Return response
+	makeJSONResponse(w, http.StatusOK, delivery, d.logger)
+}
+
+// NewDeadLetter creates a new DeadLetter.
+func NewDeadLetter(deadLetterService postmand.DeadLetterService, logger *zap.Logger) *DeadLetter {
+	return &DeadLetter{
+		deadLetterService: deadLetterService,
+		logger:            logger,
+	}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	nethttp "net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/allisson/postmand"
+	"github.com/allisson/postmand/http"
+	"github.com/allisson/postmand/mocks"
+)
+
+func makeDeadLetter() postmand.DeadLetter {
+	delivery := makeDelivery()
+	delivery.Status = postmand.DeliveryStatusFailed
+
+	return postmand.DeadLetter{
+		Delivery:                      delivery,
+		LastAttemptError:              "",
+		LastAttemptResponseStatusCode: 500,
+	}
+}
+
+func TestDeadLetter(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	t.Run("List", func(t *testing.T) {
+		deadLetterService := &mocks.DeadLetterService{}
+		deadLetter := makeDeadLetter()
+		listOptions := postmand.RepositoryListOptions{Filters: map[string]interface{}{}, Limit: 50, Offset: 0, OrderBy: "created_at", Order: "desc"}
+		deadLetterHandler := NewDeadLetter(deadLetterService, logger)
+		router := http.NewRouter(logger)
+		router.Get("/v1/dead-letters", deadLetterHandler.List)
+
+		deadLetterService.On("List", mock.Anything, listOptions).Return([]*postmand.DeadLetter{&deadLetter}, nil)
+		apitest.New().
+			Handler(router).
+			Get("/v1/dead-letters").
+			Expect(t).
+			Body(`{"dead_letters":[{"created_at":"0001-01-01T00:00:00Z", "delivery_attempts":0, "id":"b919ca2c-6b0f-4a22-a61f-8c882ee69323", "payload":"{}", "scheduled_at":"0001-01-01T00:00:00Z", "status":"failed", "priority":0, "expires_at":null, "updated_at":"0001-01-01T00:00:00Z", "webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2", "last_attempt_error":"", "last_attempt_response_status_code":500, "last_attempt_at":null}],"limit":50,"offset":0}`).
+			Status(nethttp.StatusOK).
+			End()
+
+		deadLetterService.AssertExpectations(t)
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		deadLetterService := &mocks.DeadLetterService{}
+		webhookID, _ := uuid.Parse("cd9b7318-36c6-4534-be84-fe78042aeaf2")
+		listOptions := postmand.RepositoryListOptions{Filters: map[string]interface{}{}, Limit: 50, Offset: 0}
+		deadLetterHandler := NewDeadLetter(deadLetterService, logger)
+		router := http.NewRouter(logger)
+		router.Get("/v1/dead-letters/stats", deadLetterHandler.Stats)
+
+		deadLetterService.On("Stats", mock.Anything, listOptions).Return([]*postmand.DeadLetterStats{{WebhookID: webhookID, Deliveries: 3}}, nil)
+		apitest.New().
+			Handler(router).
+			Get("/v1/dead-letters/stats").
+			Expect(t).
+			Body(`{"dead_letter_stats":[{"webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2","deliveries":3,"last_failed_at":"0001-01-01T00:00:00Z"}],"limit":50,"offset":0}`).
+			Status(nethttp.StatusOK).
+			End()
+
+		deadLetterService.AssertExpectations(t)
+	})
+
+	t.Run("Export", func(t *testing.T) {
+		deadLetterService := &mocks.DeadLetterService{}
+		deadLetter := makeDeadLetter()
+		listOptions := postmand.RepositoryListOptions{Filters: map[string]interface{}{"webhook_id": "cd9b7318-36c6-4534-be84-fe78042aeaf2"}, Limit: 500, Offset: 0, OrderBy: "created_at", Order: "asc"}
+		deadLetterHandler := NewDeadLetter(deadLetterService, logger)
+		router := http.NewRouter(logger)
+		router.Get("/v1/dead-letters/export", deadLetterHandler.Export)
+
+		deadLetterService.On("List", mock.Anything, listOptions).Return([]*postmand.DeadLetter{&deadLetter, &deadLetter}, nil)
+		line := `{"id":"b919ca2c-6b0f-4a22-a61f-8c882ee69323","webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2","payload":"{}","scheduled_at":"0001-01-01T00:00:00Z","delivery_attempts":0,"status":"failed","priority":0,"expires_at":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z","last_attempt_error":"","last_attempt_response_status_code":500,"last_attempt_at":null}` + "\n"
+		apitest.New().
+			Handler(router).
+			Get("/v1/dead-letters/export").
+			Query("webhook_id", "cd9b7318-36c6-4534-be84-fe78042aeaf2").
+			Expect(t).
+			Header("Content-Type", "application/x-ndjson; charset=utf-8").
+			Body(line + line).
+			Status(nethttp.StatusOK).
+			End()
+
+		deadLetterService.AssertExpectations(t)
+	})
+
+	t.Run("Get with dead letter not found", func(t *testing.T) {
+		deadLetterService := &mocks.DeadLetterService{}
+		deadLetter := makeDeadLetter()
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": deadLetter.ID}}
+		deadLetterHandler := NewDeadLetter(deadLetterService, logger)
+		router := http.NewRouter(logger)
+		router.Get("/v1/dead-letters/{delivery_id}", deadLetterHandler.Get)
+
+		deadLetterService.On("Get", mock.Anything, getOptions).Return(nil, postmand.ErrDeadLetterNotFound)
+		apitest.New().
+			Handler(router).
+			Get("/v1/dead-letters/b919ca2c-6b0f-4a22-a61f-8c882ee69323").
+			Expect(t).
+			Body(`{"code":11, "message":"dead letter not found"}`).
+			Status(nethttp.StatusNotFound).
+			End()
+
+		deadLetterService.AssertExpectations(t)
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		deadLetterService := &mocks.DeadLetterService{}
+		deadLetterHandler := NewDeadLetter(deadLetterService, logger)
+		delivery := makeDelivery()
+		delivery.Status = postmand.DeliveryStatusPending
+		router := http.NewRouter(logger)
+		router.Post("/v1/dead-letters/{delivery_id}/replay", deadLetterHandler.Replay)
+
+		deadLetterService.On("Replay", mock.Anything, delivery.ID, true).Return(&delivery, nil)
+		apitest.New().
+			Handler(router).
+			Post("/v1/dead-letters/b919ca2c-6b0f-4a22-a61f-8c882ee69323/replay").
+			JSON(`{"reset_delivery_attempts":true}`).
+			Expect(t).
+			Body(`{"created_at":"0001-01-01T00:00:00Z", "delivery_attempts":0, "id":"b919ca2c-6b0f-4a22-a61f-8c882ee69323", "payload":"{}", "scheduled_at":"0001-01-01T00:00:00Z", "status":"pending", "priority":0, "expires_at":null, "updated_at":"0001-01-01T00:00:00Z", "webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2"}`).
+			Status(nethttp.StatusOK).
+			End()
+
+		deadLetterService.AssertExpectations(t)
+	})
+
+	t.Run("Discard", func(t *testing.T) {
+		deadLetterService := &mocks.DeadLetterService{}
+		deadLetterHandler := NewDeadLetter(deadLetterService, logger)
+		delivery := makeDelivery()
+		delivery.Status = postmand.DeliveryStatusDiscarded
+		router := http.NewRouter(logger)
+		router.Post("/v1/dead-letters/{delivery_id}/discard", deadLetterHandler.Discard)
+
+		deadLetterService.On("Discard", mock.Anything, delivery.ID).Return(&delivery, nil)
+		apitest.New().
+			Handler(router).
+			Post("/v1/dead-letters/b919ca2c-6b0f-4a22-a61f-8c882ee69323/discard").
+			Expect(t).
+			Body(`{"created_at":"0001-01-01T00:00:00Z", "delivery_attempts":0, "id":"b919ca2c-6b0f-4a22-a61f-8c882ee69323", "payload":"{}", "scheduled_at":"0001-01-01T00:00:00Z", "status":"discarded", "priority":0, "expires_at":null, "updated_at":"0001-01-01T00:00:00Z", "webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2"}`).
+			Status(nethttp.StatusOK).
+			End()
+
+		deadLetterService.AssertExpectations(t)
+	})
+
+	t.Run("Discard with dead letter not found", func(t *testing.T) {
+		deadLetterService := &mocks.DeadLetterService{}
+		deadLetterHandler := NewDeadLetter(deadLetterService, logger)
+		delivery := makeDelivery()
+		router := http.NewRouter(logger)
+		router.Post("/v1/dead-letters/{delivery_id}/discard", deadLetterHandler.Discard)
+
+		deadLetterService.On("Discard", mock.Anything, delivery.ID).Return(nil, postmand.ErrDeadLetterNotFound)
+		apitest.New().
+			Handler(router).
+			Post("/v1/dead-letters/b919ca2c-6b0f-4a22-a61f-8c882ee69323/discard").
+			Expect(t).
+			Body(`{"code":11, "message":"dead letter not found"}`).
+			Status(nethttp.StatusNotFound).
+			End()
+
+		deadLetterService.AssertExpectations(t)
+	})
+}
@@ -2,8 +2,11 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
@@ -16,6 +19,46 @@ type deliveryList struct {
 	Offset     int                  `json:"offset"`
 } //@name DeliveryList
 
+type deliveryCreate struct {
+	postmand.Delivery
+	Priority *int `json:"priority"`
+	Delay    int  `json:"delay"`
+} //@name DeliveryCreate
+
+// Validate implements ozzo validation Validatable interface
+func (d deliveryCreate) Validate() error {
+	scheduledAt := time.Now().UTC().Add(time.Duration(d.Delay) * time.Second)
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.Delivery),
+		validation.Field(
+			&d.ExpiresAt,
+			validation.When(d.Delay > 0, validation.Min(scheduledAt).Exclusive().Error("must be after the delay")),
+		),
+		validation.Field(&d.Priority, validation.Min(postmand.DeliveryPriorityMin), validation.Max(postmand.DeliveryPriorityMax)),
+		validation.Field(
+			&d.Delay,
+			validation.Min(0),
+			validation.Max(int(postmand.DeliveryScheduleMaxDelay.Seconds())),
+			validation.When(!d.ScheduledAt.IsZero(), validation.Empty.Error("must be blank when scheduled_at is defined")),
+		),
+	)
+}
+
+type deliveryRetry struct {
+	ResetDeliveryAttempts bool `json:"reset_delivery_attempts"`
+} //@name DeliveryRetry
+
+type deliveryReplayToURL struct {
+	URL string `json:"url"`
+} //@name DeliveryReplayToURL
+
+// Validate implements ozzo validation Validatable interface
+func (d deliveryReplayToURL) Validate() error {
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.URL, validation.Required, is.URL),
+	)
+}
+
 // Delivery implements rest interface for delivery.
 type Delivery struct {
 	deliveryService postmand.DeliveryService
@@ -31,16 +74,19 @@ type Delivery struct {
 // @Param limit query int false "The limit indicates the maximum number of items to return"
 // @Param offset query int false "The offset indicates the starting position of the query in relation to the complete set of unpaginated items"
 // @Param webhook_id query string false "Filter by webhook_id field"
+// @Param event_id query string false "Filter by event_id field"
 // @Param status query string false "Filter by status field"
 // @Param created_at.gt query string false "Return results where the created_at field is greater than this value"
 // @Param created_at.gte query string false "Return results where the created_at field is greater than or equal to this value"
 // @Param created_at.lt query string false "Return results where the created_at field is less than this value"
 // @Param created_at.lte query string false "Return results where the created_at field is less than or equal to this value"
+// @Param payload.field query string false "Filter by a field of the JSON payload, eg: payload.order_id=1234 or payload.customer.id=1"
 // @Success 200 {object} deliveryList
 // @Failure 500 {object} errorResponse
 // @Router /deliveries [get]
 func (d Delivery) List(w http.ResponseWriter, r *http.Request) {
-	listOptions := makeListOptions(r, []string{"webhook_id", "status", "created_at.gt", "created_at.gte", "created_at.lt", "created_at.lte"})
+	listOptions := makeListOptions(r, []string{"webhook_id", "event_id", "status", "created_at.gt", "created_at.gte", "created_at.lt", "created_at.lte"})
+	addPayloadFilters(r, &listOptions)
 	listOptions.OrderBy = "created_at"
 	listOptions.Order = "desc"
 
@@ -116,21 +162,61 @@ func (d Delivery) Get(w http.ResponseWriter, r *http.Request) {
 // @Tags deliveries
 // @Accept json
 // @Produce json
-// @Param delivery body postmand.Delivery true "Add delivery"
+// @Param delivery body deliveryCreate true "Add delivery"
+// @Param Idempotency-Key header string false "Idempotency key, the original delivery is returned when the key was already used by the webhook"
+// @Success 200 {object} postmand.Delivery "The original delivery of the idempotency key"
 // @Success 201 {object} postmand.Delivery
 // @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
 // @Failure 500 {object} errorResponse
 // @Router /deliveries [post]
 func (d Delivery) Create(w http.ResponseWriter, r *http.Request) {
 	// Parse request
-	delivery := postmand.Delivery{}
-	if er := readBodyJSON(r, &delivery, d.logger); er != nil {
+	dc := deliveryCreate{}
+	if er := readBodyJSON(r, &dc, d.logger); er != nil {
 		makeErrorResponse(w, er, d.logger)
 		return
 	}
+	delivery := dc.Delivery
+	if dc.Priority != nil {
+		delivery.Priority = *dc.Priority
+	}
+	if dc.Delay > 0 {
+		delivery.ScheduledAt = time.Now().UTC().Add(time.Duration(dc.Delay) * time.Second)
+	}
+	if idempotencyKey := r.Header.Get("Idempotency-Key"); idempotencyKey != "" {
+		if delivery.IdempotencyKey != "" && delivery.IdempotencyKey != idempotencyKey {
+			er := errorResponses["request_validation_failed"]
+			er.Details = "idempotency_key: must match the Idempotency-Key header."
+			makeErrorResponse(w, &er, d.logger)
+			return
+		}
+		delivery.IdempotencyKey = idempotencyKey
+		if err := validation.Validate(
+			delivery.IdempotencyKey,
+			validation.Length(1, postmand.DeliveryIdempotencyKeyMaxLength),
+		); err != nil {
+			er := errorResponses["request_validation_failed"]
+			er.Details = "idempotency_key: " + err.Error() + "."
+			makeErrorResponse(w, &er, d.logger)
+			return
+		}
+	}
 
 	// Call service
-	if err := d.deliveryService.Create(r.Context(), &delivery); err != nil {
+	created, err := d.deliveryService.Create(r.Context(), &delivery, dc.Priority == nil)
+	if err != nil {
+		if err == postmand.ErrWebhookNotFound {
+			er := errorResponses["webhook_not_found"]
+			makeErrorResponse(w, &er, d.logger)
+			return
+		}
+		if payloadErr, ok := err.(*postmand.PayloadValidationError); ok {
+			er := errorResponses["request_validation_failed"]
+			er.Details = payloadErr.Error()
+			makeErrorResponse(w, &er, d.logger)
+			return
+		}
 		d.logger.Error(
 			"service-error",
 			zap.String("name", "DeliveryService"),
@@ -139,10 +225,15 @@ func (d Delivery) Create(w http.ResponseWriter, r *http.Request) {
 		)
 		er := errorResponses["internal_server_error"]
 		makeErrorResponse(w, &er, d.logger)
+		return
 	}
 
-	// Return response
-	makeJSONResponse(w, http.StatusCreated, delivery, d.logger)
+	// Return response, the original delivery of an idempotency key is returned with 200
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+	makeJSONResponse(w, status, delivery, d.logger)
 }
 
 // Delete delivery.
@@ -180,6 +271,171 @@ func (d Delivery) Delete(w http.ResponseWriter, r *http.Request) {
 	makeResponse(w, []byte(""), http.StatusNoContent, "application/json", d.logger)
 }
 
+// Cancel delivery.
+// Cancel godoc
+// @Summary Cancel a pending delivery
+// @Tags deliveries
+// @Accept json
+// @Produce json
+// @Param delivery_id path string true "Delivery ID"
+// @Success 200 {object} postmand.Delivery
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /deliveries/{delivery_id}/cancel [post]
+func (d Delivery) Cancel(w http.ResponseWriter, r *http.Request) {
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "delivery_id"))
+	if err != nil {
+		er := errorResponses["invalid_id"]
+		makeErrorResponse(w, &er, d.logger)
+		return
+	}
+
+	// Call service
+	delivery, err := d.deliveryService.Cancel(r.Context(), deliveryID)
+	if err != nil {
+		switch err {
+		case postmand.ErrDeliveryNotFound:
+			er := errorResponses["delivery_not_found"]
+			makeErrorResponse(w, &er, d.logger)
+			return
+		case postmand.ErrDeliveryNotCancellable:
+			er := errorResponses["delivery_not_cancellable"]
+			makeErrorResponse(w, &er, d.logger)
+			return
+		}
+		d.logger.Error(
+			"service-error",
+			zap.String("name", "DeliveryService"),
+			zap.String("method", "Cancel"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, d.logger)
+		return
+	}
+
+	// Return response
+	makeJSONResponse(w, http.StatusOK, delivery, d.logger)
+}
+
+// Retry delivery.
+// Retry godoc
+// @Summary Retry a succeeded or failed delivery
+// @Tags deliveries
+// @Accept json
+// @Produce json
+// @Param delivery_id path string true "Delivery ID"
+// @Param delivery body deliveryRetry false "Retry options"
+// @Success 200 {object} postmand.Delivery
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /deliveries/{delivery_id}/retry [post]
+func (d Delivery) Retry(w http.ResponseWriter, r *http.Request) {
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "delivery_id"))
+	if err != nil {
+		er := errorResponses["invalid_id"]
+		makeErrorResponse(w, &er, d.logger)
+		return
+	}
+
+	// Parse request (the body is optional)
+	dr := deliveryRetry{}
+	if r.ContentLength > 0 {
+		if er := readBodyJSON(r, &dr, d.logger); er != nil {
+			makeErrorResponse(w, er, d.logger)
+			return
+		}
+	}
+
+	// Call service
+	delivery, err := d.deliveryService.Retry(r.Context(), deliveryID, dr.ResetDeliveryAttempts)
+	if err != nil {
+		switch err {
+		case postmand.ErrDeliveryNotFound:
+			er := errorResponses["delivery_not_found"]
+			makeErrorResponse(w, &er, d.logger)
+			return
+		case postmand.ErrDeliveryNotRetryable:
+			er := errorResponses["delivery_not_retryable"]
+			makeErrorResponse(w, &er, d.logger)
+			return
+		}
+		d.logger.Error(
+			"service-error",
+			zap.String("name", "DeliveryService"),
+			zap.String("method", "Retry"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, d.logger)
+		return
+	}
+
+	// Return response
+	makeJSONResponse(w, http.StatusOK, delivery, d.logger)
+}
+
+// ReplayToURL delivery.
+// ReplayToURL godoc
+// @Summary Send the payload of a delivery to an alternate url
+// @Description The delivery is not changed, the result is recorded as a delivery attempt with the replay kind.
+// @Tags deliveries
+// @Accept json
+// @Produce json
+// @Param delivery_id path string true "Delivery ID"
+// @Param delivery body deliveryReplayToURL true "Replay options"
+// @Success 201 {object} postmand.DeliveryAttempt
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /deliveries/{delivery_id}/replay-to-url [post]
+func (d Delivery) ReplayToURL(w http.ResponseWriter, r *http.Request) {
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "delivery_id"))
+	if err != nil {
+		er := errorResponses["invalid_id"]
+		makeErrorResponse(w, &er, d.logger)
+		return
+	}
+
+	// Parse request
+	dr := deliveryReplayToURL{}
+	if er := readBodyJSON(r, &dr, d.logger); er != nil {
+		makeErrorResponse(w, er, d.logger)
+		return
+	}
+
+	// Call service
+	deliveryAttempt, err := d.deliveryService.ReplayToURL(r.Context(), deliveryID, dr.URL)
+	if err != nil {
+		switch err {
+		case postmand.ErrDeliveryNotFound:
+			er := errorResponses["delivery_not_found"]
+			makeErrorResponse(w, &er, d.logger)
+			return
+		case postmand.ErrWebhookNotVerified:
+			er := errorResponses["webhook_not_verified"]
+			makeErrorResponse(w, &er, d.logger)
+			return
+		}
+		d.logger.Error(
+			"service-error",
+			zap.String("name", "DeliveryService"),
+			zap.String("method", "ReplayToURL"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, d.logger)
+		return
+	}
+
+	// Return response
+	makeJSONResponse(w, http.StatusCreated, deliveryAttempt, d.logger)
+}
+
 // NewDelivery creates a new Delivery.
 func NewDelivery(deliveryService postmand.DeliveryService, logger *zap.Logger) *Delivery {
 	return &Delivery{
@@ -32,7 +32,9 @@ type DeliveryAttempt struct {
 // @Param offset query int false "The offset indicates the starting position of the query in relation to the complete set of unpaginated items"
 // @Param webhook_id query string false "Filter by webhook_id"
 // @Param delivery_id query string false "Filter by delivery_id"
+// @Param batch_id query string false "Filter by batch_id"
 // @Param success query boolean false "Filter by success"
+// @Param kind query string false "Filter by kind (dispatch or replay)"
 // @Param created_at.gt query string false "Return results where the created_at field is greater than this value"
 // @Param created_at.gte query string false "Return results where the created_at field is greater than or equal to this value"
 // @Param created_at.lt query string false "Return results where the created_at field is less than this value"
@@ -41,7 +43,7 @@ type DeliveryAttempt struct {
 // @Failure 500 {object} errorResponse
 // @Router /delivery-attempts [get]
 func (d DeliveryAttempt) List(w http.ResponseWriter, r *http.Request) {
-	listOptions := makeListOptions(r, []string{"webhook_id", "delivery_id", "success", "created_at.gt", "created_at.gte", "created_at.lt", "created_at.lte"})
+	listOptions := makeListOptions(r, []string{"webhook_id", "delivery_id", "batch_id", "success", "kind", "created_at.gt", "created_at.gte", "created_at.lt", "created_at.lte"})
 	listOptions.OrderBy = "created_at"
 	listOptions.Order = "desc"
 
@@ -2,9 +2,12 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	nethttp "net/http"
 	"testing"
+	"time"
 
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/google/uuid"
 	"github.com/steinfletcher/apitest"
 	"github.com/stretchr/testify/mock"
@@ -41,7 +44,30 @@ func TestDelivery(t *testing.T) {
 			Handler(router).
 			Get("/v1/deliveries").
 			Expect(t).
-			Body(`{"deliveries":[{"id":"00000000-0000-0000-0000-000000000000","webhook_id":"00000000-0000-0000-0000-000000000000","payload":"","scheduled_at":"0001-01-01T00:00:00Z","delivery_attempts":0,"status":"","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}],"limit":50,"offset":0}`).
+			Body(`{"deliveries":[{"id":"00000000-0000-0000-0000-000000000000","webhook_id":"00000000-0000-0000-0000-000000000000","payload":"","scheduled_at":"0001-01-01T00:00:00Z","delivery_attempts":0,"status":"","priority":0,"expires_at":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}],"limit":50,"offset":0}`).
+			Status(nethttp.StatusOK).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
+
+	t.Run("List with payload filters", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		listOptions := postmand.RepositoryListOptions{Filters: map[string]interface{}{"payload.order_id": "1234", "payload.customer.id": "c1"}, Limit: 50, Offset: 0, OrderBy: "created_at", Order: "desc"}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		router := http.NewRouter(logger)
+		router.Get("/v1/deliveries", deliveryHandler.List)
+
+		deliveryService.On("List", mock.Anything, listOptions).Return([]*postmand.Delivery{}, nil)
+		apitest.New().
+			Handler(router).
+			Get("/v1/deliveries").
+			Query("payload.order_id", "1234").
+			Query("payload.customer.id", "c1").
+			Query("payload.", "invalid").
+			Query("payload_other", "invalid").
+			Expect(t).
+			Body(`{"deliveries":[],"limit":50,"offset":0}`).
 			Status(nethttp.StatusOK).
 			End()
 
@@ -61,7 +87,7 @@ func TestDelivery(t *testing.T) {
 			Handler(router).
 			Get("/v1/deliveries/b919ca2c-6b0f-4a22-a61f-8c882ee69323").
 			Expect(t).
-			Body(`{"created_at":"0001-01-01T00:00:00Z", "delivery_attempts":0, "id":"b919ca2c-6b0f-4a22-a61f-8c882ee69323", "payload":"{}", "scheduled_at":"0001-01-01T00:00:00Z", "status":"", "updated_at":"0001-01-01T00:00:00Z", "webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2"}`).
+			Body(`{"created_at":"0001-01-01T00:00:00Z", "delivery_attempts":0, "id":"b919ca2c-6b0f-4a22-a61f-8c882ee69323", "payload":"{}", "scheduled_at":"0001-01-01T00:00:00Z", "status":"", "priority":0, "expires_at":null, "updated_at":"0001-01-01T00:00:00Z", "webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2"}`).
 			Status(nethttp.StatusOK).
 			End()
 
@@ -94,19 +120,190 @@ func TestDelivery(t *testing.T) {
 		router := http.NewRouter(logger)
 		router.Post("/v1/deliveries", deliveryHandler.Create)
 
-		deliveryService.On("Create", mock.Anything, &delivery).Return(nil)
+		deliveryService.On("Create", mock.Anything, &delivery, false).Return(true, nil)
+		apitest.New().
+			Handler(router).
+			Post("/v1/deliveries").
+			JSON(jsonDelivery).
+			Expect(t).
+			Body(`{"created_at":"0001-01-01T00:00:00Z", "delivery_attempts":0, "id":"b919ca2c-6b0f-4a22-a61f-8c882ee69323", "payload":"{}", "scheduled_at":"0001-01-01T00:00:00Z", "status":"", "priority":0, "expires_at":null, "updated_at":"0001-01-01T00:00:00Z", "webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2"}`).
+			Status(nethttp.StatusCreated).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
+
+	t.Run("Create with invalid payload", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		delivery := makeDelivery()
+		jsonDelivery, _ := json.Marshal(&delivery)
+		router := http.NewRouter(logger)
+		router.Post("/v1/deliveries", deliveryHandler.Create)
+
+		payloadErr := &postmand.PayloadValidationError{Errors: validation.Errors{"payload.order_id": errors.New("is required")}}
+		deliveryService.On("Create", mock.Anything, &delivery, false).Return(false, payloadErr)
+		apitest.New().
+			Handler(router).
+			Post("/v1/deliveries").
+			JSON(jsonDelivery).
+			Expect(t).
+			Body(`{"code":4, "message":"request validation failed", "details":"payload.order_id: is required."}`).
+			Status(nethttp.StatusBadRequest).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
+
+	t.Run("Create with delay", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		router := http.NewRouter(logger)
+		router.Post("/v1/deliveries", deliveryHandler.Create)
+
+		minScheduledAt := time.Now().UTC().Add(900 * time.Second)
+		scheduledWithDelay := mock.MatchedBy(func(delivery *postmand.Delivery) bool {
+			return !delivery.ScheduledAt.Before(minScheduledAt) && delivery.ScheduledAt.Before(minScheduledAt.Add(time.Minute))
+		})
+		deliveryService.On("Create", mock.Anything, scheduledWithDelay, true).Return(true, nil)
+		apitest.New().
+			Handler(router).
+			Post("/v1/deliveries").
+			JSON(`{"webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2", "payload":"{}", "delay":900}`).
+			Expect(t).
+			Status(nethttp.StatusCreated).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
+
+	t.Run("Create with delay and scheduled_at", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		router := http.NewRouter(logger)
+		router.Post("/v1/deliveries", deliveryHandler.Create)
+
+		scheduledAt := time.Now().UTC().Add(time.Hour).Format(time.RFC3339)
+		apitest.New().
+			Handler(router).
+			Post("/v1/deliveries").
+			JSON(`{"webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2", "payload":"{}", "scheduled_at":"` + scheduledAt + `", "delay":60}`).
+			Expect(t).
+			Body(`{"code":4, "message":"request validation failed", "details":"delay: must be blank when scheduled_at is defined."}`).
+			Status(nethttp.StatusBadRequest).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
+
+	t.Run("Create with delay after expires_at", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		router := http.NewRouter(logger)
+		router.Post("/v1/deliveries", deliveryHandler.Create)
+
+		expiresAt := time.Now().UTC().Add(time.Minute).Format(time.RFC3339)
+		apitest.New().
+			Handler(router).
+			Post("/v1/deliveries").
+			JSON(`{"webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2", "payload":"{}", "expires_at":"` + expiresAt + `", "delay":900}`).
+			Expect(t).
+			Body(`{"code":4, "message":"request validation failed", "details":"expires_at: must be after the delay."}`).
+			Status(nethttp.StatusBadRequest).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
+
+	t.Run("Create with idempotency key header", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		delivery := makeDelivery()
+		jsonDelivery, _ := json.Marshal(&delivery)
+		delivery.IdempotencyKey = "key"
+		router := http.NewRouter(logger)
+		router.Post("/v1/deliveries", deliveryHandler.Create)
+
+		deliveryService.On("Create", mock.Anything, &delivery, false).Return(true, nil)
 		apitest.New().
 			Handler(router).
 			Post("/v1/deliveries").
+			Header("Idempotency-Key", "key").
 			JSON(jsonDelivery).
 			Expect(t).
-			Body(`{"created_at":"0001-01-01T00:00:00Z", "delivery_attempts":0, "id":"b919ca2c-6b0f-4a22-a61f-8c882ee69323", "payload":"{}", "scheduled_at":"0001-01-01T00:00:00Z", "status":"", "updated_at":"0001-01-01T00:00:00Z", "webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2"}`).
+			Body(`{"created_at":"0001-01-01T00:00:00Z", "delivery_attempts":0, "id":"b919ca2c-6b0f-4a22-a61f-8c882ee69323", "payload":"{}", "scheduled_at":"0001-01-01T00:00:00Z", "status":"", "priority":0, "expires_at":null, "idempotency_key":"key", "updated_at":"0001-01-01T00:00:00Z", "webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2"}`).
 			Status(nethttp.StatusCreated).
 			End()
 
 		deliveryService.AssertExpectations(t)
 	})
 
+	t.Run("Create with used idempotency key", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		delivery := makeDelivery()
+		jsonDelivery, _ := json.Marshal(&delivery)
+		delivery.IdempotencyKey = "key"
+		router := http.NewRouter(logger)
+		router.Post("/v1/deliveries", deliveryHandler.Create)
+
+		deliveryService.On("Create", mock.Anything, &delivery, false).Return(false, nil)
+		apitest.New().
+			Handler(router).
+			Post("/v1/deliveries").
+			Header("Idempotency-Key", "key").
+			JSON(jsonDelivery).
+			Expect(t).
+			Body(`{"created_at":"0001-01-01T00:00:00Z", "delivery_attempts":0, "id":"b919ca2c-6b0f-4a22-a61f-8c882ee69323", "payload":"{}", "scheduled_at":"0001-01-01T00:00:00Z", "status":"", "priority":0, "expires_at":null, "idempotency_key":"key", "updated_at":"0001-01-01T00:00:00Z", "webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2"}`).
+			Status(nethttp.StatusOK).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
+
+	t.Run("Create with idempotency key mismatch", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		delivery := makeDelivery()
+		delivery.IdempotencyKey = "key"
+		jsonDelivery, _ := json.Marshal(&delivery)
+		router := http.NewRouter(logger)
+		router.Post("/v1/deliveries", deliveryHandler.Create)
+
+		apitest.New().
+			Handler(router).
+			Post("/v1/deliveries").
+			Header("Idempotency-Key", "other-key").
+			JSON(jsonDelivery).
+			Expect(t).
+			Body(`{"code":4, "message":"request validation failed", "details":"idempotency_key: must match the Idempotency-Key header."}`).
+			Status(nethttp.StatusBadRequest).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
+
+	t.Run("Create with webhook not found", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		delivery := makeDelivery()
+		jsonDelivery, _ := json.Marshal(&delivery)
+		router := http.NewRouter(logger)
+		router.Post("/v1/deliveries", deliveryHandler.Create)
+
+		deliveryService.On("Create", mock.Anything, &delivery, false).Return(false, postmand.ErrWebhookNotFound)
+		apitest.New().
+			Handler(router).
+			Post("/v1/deliveries").
+			JSON(jsonDelivery).
+			Expect(t).
+			Body(`{"code":5, "message":"webhook not found"}`).
+			Status(nethttp.StatusNotFound).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
+
 	t.Run("Delete", func(t *testing.T) {
 		deliveryService := &mocks.DeliveryService{}
 		deliveryHandler := NewDelivery(deliveryService, logger)
@@ -124,4 +321,180 @@ func TestDelivery(t *testing.T) {
 
 		deliveryService.AssertExpectations(t)
 	})
+	t.Run("Cancel", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		delivery := makeDelivery()
+		delivery.Status = postmand.DeliveryStatusCancelled
+		router := http.NewRouter(logger)
+		router.Post("/v1/deliveries/{delivery_id}/cancel", deliveryHandler.Cancel)
+
+		deliveryService.On("Cancel", mock.Anything, delivery.ID).Return(&delivery, nil)
+		apitest.New().
+			Handler(router).
+			Post("/v1/deliveries/b919ca2c-6b0f-4a22-a61f-8c882ee69323/cancel").
+			Expect(t).
+			Body(`{"created_at":"0001-01-01T00:00:00Z", "delivery_attempts":0, "id":"b919ca2c-6b0f-4a22-a61f-8c882ee69323", "payload":"{}", "scheduled_at":"0001-01-01T00:00:00Z", "status":"cancelled", "priority":0, "expires_at":null, "updated_at":"0001-01-01T00:00:00Z", "webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2"}`).
+			Status(nethttp.StatusOK).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
+
+	t.Run("Cancel with delivery not cancellable", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		delivery := makeDelivery()
+		router := http.NewRouter(logger)
+		router.Post("/v1/deliveries/{delivery_id}/cancel", deliveryHandler.Cancel)
+
+		deliveryService.On("Cancel", mock.Anything, delivery.ID).Return(&delivery, postmand.ErrDeliveryNotCancellable)
+		apitest.New().
+			Handler(router).
+			Post("/v1/deliveries/b919ca2c-6b0f-4a22-a61f-8c882ee69323/cancel").
+			Expect(t).
+			Body(`{"code":8, "message":"delivery not cancellable"}`).
+			Status(nethttp.StatusConflict).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
+	t.Run("Retry", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		delivery := makeDelivery()
+		delivery.Status = postmand.DeliveryStatusPending
+		router := http.NewRouter(logger)
+		router.Post("/v1/deliveries/{delivery_id}/retry", deliveryHandler.Retry)
+
+		deliveryService.On("Retry", mock.Anything, delivery.ID, false).Return(&delivery, nil)
+		apitest.New().
+			Handler(router).
+			Post("/v1/deliveries/b919ca2c-6b0f-4a22-a61f-8c882ee69323/retry").
+			Expect(t).
+			Body(`{"created_at":"0001-01-01T00:00:00Z", "delivery_attempts":0, "id":"b919ca2c-6b0f-4a22-a61f-8c882ee69323", "payload":"{}", "scheduled_at":"0001-01-01T00:00:00Z", "status":"pending", "priority":0, "expires_at":null, "updated_at":"0001-01-01T00:00:00Z", "webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2"}`).
+			Status(nethttp.StatusOK).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
+
+	t.Run("Retry with reset delivery attempts", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		delivery := makeDelivery()
+		delivery.Status = postmand.DeliveryStatusPending
+		router := http.NewRouter(logger)
+		router.Post("/v1/deliveries/{delivery_id}/retry", deliveryHandler.Retry)
+
+		deliveryService.On("Retry", mock.Anything, delivery.ID, true).Return(&delivery, nil)
+		apitest.New().
+			Handler(router).
+			Post("/v1/deliveries/b919ca2c-6b0f-4a22-a61f-8c882ee69323/retry").
+			JSON(`{"reset_delivery_attempts": true}`).
+			Expect(t).
+			Status(nethttp.StatusOK).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
+
+	t.Run("Retry with delivery not retryable", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		delivery := makeDelivery()
+		router := http.NewRouter(logger)
+		router.Post("/v1/deliveries/{delivery_id}/retry", deliveryHandler.Retry)
+
+		deliveryService.On("Retry", mock.Anything, delivery.ID, false).Return(&delivery, postmand.ErrDeliveryNotRetryable)
+		apitest.New().
+			Handler(router).
+			Post("/v1/deliveries/b919ca2c-6b0f-4a22-a61f-8c882ee69323/retry").
+			Expect(t).
+			Body(`{"code":9, "message":"delivery not retryable"}`).
+			Status(nethttp.StatusConflict).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
+
+	t.Run("ReplayToURL", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		deliveryAttempt := makeDeliveryAttempt()
+		deliveryAttempt.Kind = postmand.DeliveryAttemptKindReplay
+		deliveryAttempt.URL = "http://localhost:9000/webhook"
+		router := http.NewRouter(logger)
+		router.Post("/v1/deliveries/{delivery_id}/replay-to-url", deliveryHandler.ReplayToURL)
+
+		deliveryService.On("ReplayToURL", mock.Anything, deliveryAttempt.DeliveryID, "http://localhost:9000/webhook").Return(&deliveryAttempt, nil)
+		apitest.New().
+			Handler(router).
+			Post("/v1/deliveries/b919ca2c-6b0f-4a22-a61f-8c882ee69323/replay-to-url").
+			JSON(`{"url":"http://localhost:9000/webhook"}`).
+			Expect(t).
+			Body(`{"id":"97087247-d89d-410e-b915-740b4c6d9d99","webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2","delivery_id":"b919ca2c-6b0f-4a22-a61f-8c882ee69323","kind":"replay","url":"http://localhost:9000/webhook","compression":"","raw_request":"", "raw_response":"","response_status_code":0,"execution_duration":0,"success":false,"error":"","created_at":"0001-01-01T00:00:00Z"}`).
+			Status(nethttp.StatusCreated).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
+
+	t.Run("ReplayToURL with invalid url", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		router := http.NewRouter(logger)
+		router.Post("/v1/deliveries/{delivery_id}/replay-to-url", deliveryHandler.ReplayToURL)
+
+		apitest.New().
+			Handler(router).
+			Post("/v1/deliveries/b919ca2c-6b0f-4a22-a61f-8c882ee69323/replay-to-url").
+			JSON(`{"url":"localhost"}`).
+			Expect(t).
+			Body(`{"code":4, "message":"request validation failed", "details":"url: must be a valid URL."}`).
+			Status(nethttp.StatusBadRequest).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
+
+	t.Run("ReplayToURL with delivery not found", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		delivery := makeDelivery()
+		router := http.NewRouter(logger)
+		router.Post("/v1/deliveries/{delivery_id}/replay-to-url", deliveryHandler.ReplayToURL)
+
+		deliveryService.On("ReplayToURL", mock.Anything, delivery.ID, "http://localhost:9000/webhook").Return(nil, postmand.ErrDeliveryNotFound)
+		apitest.New().
+			Handler(router).
+			Post("/v1/deliveries/b919ca2c-6b0f-4a22-a61f-8c882ee69323/replay-to-url").
+			JSON(`{"url":"http://localhost:9000/webhook"}`).
+			Expect(t).
+			Body(`{"code":6, "message":"delivery not found"}`).
+			Status(nethttp.StatusNotFound).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
+
+	t.Run("ReplayToURL with webhook not verified", func(t *testing.T) {
+		deliveryService := &mocks.DeliveryService{}
+		deliveryHandler := NewDelivery(deliveryService, logger)
+		delivery := makeDelivery()
+		router := http.NewRouter(logger)
+		router.Post("/v1/deliveries/{delivery_id}/replay-to-url", deliveryHandler.ReplayToURL)
+
+		deliveryService.On("ReplayToURL", mock.Anything, delivery.ID, "http://localhost:9000/webhook").Return(nil, postmand.ErrWebhookNotVerified)
+		apitest.New().
+			Handler(router).
+			Post("/v1/deliveries/b919ca2c-6b0f-4a22-a61f-8c882ee69323/replay-to-url").
+			JSON(`{"url":"http://localhost:9000/webhook"}`).
+			Expect(t).
+			Body(`{"code":14, "message":"webhook not verified"}`).
+			Status(nethttp.StatusConflict).
+			End()
+
+		deliveryService.AssertExpectations(t)
+	})
 }
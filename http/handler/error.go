@@ -12,6 +12,13 @@ const (
 	webhookNotFoundCode
 	deliveryNotFoundCode
 	deliveryAttemptNotFoundCode
+	deliveryNotCancellableCode
+	deliveryNotRetryableCode
+	replayJobNotFoundCode
+	deadLetterNotFoundCode
+	webhookVerificationFailedCode
+	eventNotFoundCode
+	webhookNotVerifiedCode
 )
 
 var errorResponses = map[string]errorResponse{
@@ -50,6 +57,41 @@ var errorResponses = map[string]errorResponse{
 		Message:    "delivery attempt not found",
 		StatusCode: http.StatusNotFound,
 	},
+	"delivery_not_cancellable": {
+		Code:       deliveryNotCancellableCode,
+		Message:    "delivery not cancellable",
+		StatusCode: http.StatusConflict,
+	},
+	"delivery_not_retryable": {
+		Code:       deliveryNotRetryableCode,
+		Message:    "delivery not retryable",
+		StatusCode: http.StatusConflict,
+	},
+	"replay_job_not_found": {
+		Code:       replayJobNotFoundCode,
+		Message:    "replay job not found",
+		StatusCode: http.StatusNotFound,
+	},
+	"dead_letter_not_found": {
+		Code:       deadLetterNotFoundCode,
+		Message:    "dead letter not found",
+		StatusCode: http.StatusNotFound,
+	},
+	"webhook_verification_failed": {
+		Code:       webhookVerificationFailedCode,
+		Message:    "webhook verification failed",
+		StatusCode: http.StatusConflict,
+	},
+	"event_not_found": {
+		Code:       eventNotFoundCode,
+		Message:    "event not found",
+		StatusCode: http.StatusNotFound,
+	},
+	"webhook_not_verified": {
+		Code:       webhookNotVerifiedCode,
+		Message:    "webhook not verified",
+		StatusCode: http.StatusConflict,
+	},
 }
 
 type errorResponse struct {
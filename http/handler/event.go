@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/allisson/postmand"
+)
+
+type eventList struct {
+	Events []*postmand.Event `json:"events"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+} //@name EventList
+
+// Event implements rest interface for event.
+type Event struct {
+	eventService postmand.EventService
+	logger       *zap.Logger
+}
+
+// List events.
+// List godoc
+// @Summary List events
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param limit query int false "The limit indicates the maximum number of items to return"
+// @Param offset query int false "The offset indicates the starting position of the query in relation to the complete set of unpaginated items"
+// @Param event_type query string false "Filter by event_type field"
+// @Param created_at.gt query string false "Return results where the created_at field is greater than this value"
+// @Param created_at.gte query string false "Return results where the created_at field is greater than or equal to this value"
+// @Param created_at.lt query string false "Return results where the created_at field is less than this value"
+// @Param created_at.lte query string false "Return results where the created_at field is less than or equal to this value"
+// @Success 200 {object} eventList
+// @Failure 500 {object} errorResponse
+// @Router /events [get]
+func (e Event) List(w http.ResponseWriter, r *http.Request) {
+	listOptions := makeListOptions(r, []string{"event_type", "created_at.gt", "created_at.gte", "created_at.lt", "created_at.lte"})
+	listOptions.OrderBy = "created_at"
+	listOptions.Order = "desc"
+
+	// Call service
+	events, err := e.eventService.List(r.Context(), listOptions)
+	if err != nil {
+		e.logger.Error(
+			"service-error",
+			zap.String("name", "EventService"),
+			zap.String("method", "List"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, e.logger)
+		return
+	}
+
+	// Return response
+	el := eventList{
+		Events: events,
+		Limit:  listOptions.Limit,
+		Offset: listOptions.Offset,
+	}
+	makeJSONResponse(w, http.StatusOK, el, e.logger)
+}
+
+// Get event.
+// Get godoc
+// @Summary Show an event
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param event_id path string true "Event ID"
+// @Success 200 {object} postmand.Event
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /events/{event_id} [get]
+func (e Event) Get(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(chi.URLParam(r, "event_id"))
+	if err != nil {
+		er := errorResponses["invalid_id"]
+		makeErrorResponse(w, &er, e.logger)
+		return
+	}
+
+	// Call service
+	getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": eventID}}
+	event, err := e.eventService.Get(r.Context(), getOptions)
+	if err != nil {
+		if err == postmand.ErrEventNotFound {
+			er := errorResponses["event_not_found"]
+			makeErrorResponse(w, &er, e.logger)
+			return
+		}
+		e.logger.Error(
+			"service-error",
+			zap.String("name", "EventService"),
+			zap.String("method", "Get"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, e.logger)
+		return
+	}
+
+	// Return response
+	makeJSONResponse(w, http.StatusOK, event, e.logger)
+}
+
+// Create event.
+// Create godoc
+// @Summary Add an event
+// @Description One delivery is created for each active webhook subscribed to the event type, use the event_id filter to list them.
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param event body postmand.Event true "Add event"
+// @Success 201 {object} postmand.Event
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /events [post]
+func (e Event) Create(w http.ResponseWriter, r *http.Request) {
+	// Parse request
+	event := postmand.Event{}
+	if er := readBodyJSON(r, &event, e.logger); er != nil {
+		makeErrorResponse(w, er, e.logger)
+		return
+	}
+
+	// Call service
+	if err := e.eventService.Create(r.Context(), &event); err != nil {
+		if payloadErr, ok := err.(*postmand.PayloadValidationError); ok {
+			er := errorResponses["request_validation_failed"]
+			er.Details = payloadErr.Error()
+			makeErrorResponse(w, &er, e.logger)
+			return
+		}
+		e.logger.Error(
+			"service-error",
+			zap.String("name", "EventService"),
+			zap.String("method", "Create"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, e.logger)
+		return
+	}
+
+	// Return response
+	makeJSONResponse(w, http.StatusCreated, event, e.logger)
+}
+
+// NewEvent creates a new Event.
+func NewEvent(eventService postmand.EventService, logger *zap.Logger) *Event {
+	return &Event{
+		eventService: eventService,
+		logger:       logger,
+	}
+}
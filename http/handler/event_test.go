@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	nethttp "net/http"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/allisson/postmand"
+	"github.com/allisson/postmand/http"
+	"github.com/allisson/postmand/mocks"
+)
+
+func makeEvent() postmand.Event {
+	eventID, _ := uuid.Parse("9d1d4b4e-4a8c-4f36-9bb4-3f1f7a1c2e5d")
+
+	return postmand.Event{
+		ID:        eventID,
+		EventType: "order.created",
+		Payload:   `{}`,
+	}
+}
+
+func TestEvent(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	t.Run("List", func(t *testing.T) {
+		eventService := &mocks.EventService{}
+		listOptions := postmand.RepositoryListOptions{Filters: map[string]interface{}{}, Limit: 50, Offset: 0, OrderBy: "created_at", Order: "desc"}
+		eventHandler := NewEvent(eventService, logger)
+		router := http.NewRouter(logger)
+		router.Get("/v1/events", eventHandler.List)
+
+		eventService.On("List", mock.Anything, listOptions).Return([]*postmand.Event{{}}, nil)
+		apitest.New().
+			Handler(router).
+			Get("/v1/events").
+			Expect(t).
+			Body(`{"events":[{"id":"00000000-0000-0000-0000-000000000000","event_type":"","payload":"","deliveries":0,"created_at":"0001-01-01T00:00:00Z"}],"limit":50,"offset":0}`).
+			Status(nethttp.StatusOK).
+			End()
+
+		eventService.AssertExpectations(t)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		eventService := &mocks.EventService{}
+		event := makeEvent()
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": event.ID}}
+		eventHandler := NewEvent(eventService, logger)
+		router := http.NewRouter(logger)
+		router.Get("/v1/events/{event_id}", eventHandler.Get)
+
+		eventService.On("Get", mock.Anything, getOptions).Return(&event, nil)
+		apitest.New().
+			Handler(router).
+			Get("/v1/events/9d1d4b4e-4a8c-4f36-9bb4-3f1f7a1c2e5d").
+			Expect(t).
+			Body(`{"id":"9d1d4b4e-4a8c-4f36-9bb4-3f1f7a1c2e5d","event_type":"order.created","payload":"{}","deliveries":0,"created_at":"0001-01-01T00:00:00Z"}`).
+			Status(nethttp.StatusOK).
+			End()
+
+		eventService.AssertExpectations(t)
+	})
+
+	t.Run("Get with event not found", func(t *testing.T) {
+		eventService := &mocks.EventService{}
+		event := makeEvent()
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": event.ID}}
+		eventHandler := NewEvent(eventService, logger)
+		router := http.NewRouter(logger)
+		router.Get("/v1/events/{event_id}", eventHandler.Get)
+
+		eventService.On("Get", mock.Anything, getOptions).Return(nil, postmand.ErrEventNotFound)
+		apitest.New().
+			Handler(router).
+			Get("/v1/events/9d1d4b4e-4a8c-4f36-9bb4-3f1f7a1c2e5d").
+			Expect(t).
+			Body(`{"code":13, "message":"event not found"}`).
+			Status(nethttp.StatusNotFound).
+			End()
+
+		eventService.AssertExpectations(t)
+	})
+
+	t.Run("Create with invalid event type", func(t *testing.T) {
+		eventService := &mocks.EventService{}
+		eventHandler := NewEvent(eventService, logger)
+		router := http.NewRouter(logger)
+		router.Post("/v1/events", eventHandler.Create)
+
+		apitest.New().
+			Handler(router).
+			Post("/v1/events").
+			JSON(`{"event_type":"order.*","payload":"{}"}`).
+			Expect(t).
+			Body(`{"code":4, "message":"request validation failed", "details":"event_type: must be in a valid format."}`).
+			Status(nethttp.StatusBadRequest).
+			End()
+
+		eventService.AssertExpectations(t)
+	})
+
+	t.Run("Create with valid body", func(t *testing.T) {
+		eventService := &mocks.EventService{}
+		eventHandler := NewEvent(eventService, logger)
+		event := makeEvent()
+		jsonEvent, _ := json.Marshal(&event)
+		router := http.NewRouter(logger)
+		router.Post("/v1/events", eventHandler.Create)
+
+		eventService.On("Create", mock.Anything, &event).Return(nil)
+		apitest.New().
+			Handler(router).
+			Post("/v1/events").
+			JSON(jsonEvent).
+			Expect(t).
+			Body(`{"id":"9d1d4b4e-4a8c-4f36-9bb4-3f1f7a1c2e5d","event_type":"order.created","payload":"{}","deliveries":0,"created_at":"0001-01-01T00:00:00Z"}`).
+			Status(nethttp.StatusCreated).
+			End()
+
+		eventService.AssertExpectations(t)
+	})
+
+	t.Run("Create with invalid payload", func(t *testing.T) {
+		eventService := &mocks.EventService{}
+		eventHandler := NewEvent(eventService, logger)
+		event := makeEvent()
+		jsonEvent, _ := json.Marshal(&event)
+		router := http.NewRouter(logger)
+		router.Post("/v1/events", eventHandler.Create)
+
+		payloadErr := &postmand.PayloadValidationError{Errors: validation.Errors{"payload": errors.New("must be a valid JSON")}}
+		eventService.On("Create", mock.Anything, &event).Return(payloadErr)
+		apitest.New().
+			Handler(router).
+			Post("/v1/events").
+			JSON(jsonEvent).
+			Expect(t).
+			Body(`{"code":4, "message":"request validation failed", "details":"payload: must be a valid JSON."}`).
+			Status(nethttp.StatusBadRequest).
+			End()
+
+		eventService.AssertExpectations(t)
+	})
+}
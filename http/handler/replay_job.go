@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/allisson/postmand"
+)
+
+type replayJobList struct {
+	ReplayJobs []*postmand.ReplayJob `json:"replay_jobs"`
+	Limit      int                   `json:"limit"`
+	Offset     int                   `json:"offset"`
+} //@name ReplayJobList
+
+// ReplayJob implements rest interface for replay job.
+type ReplayJob struct {
+	replayJobService postmand.ReplayJobService
+	logger           *zap.Logger
+}
+
+// List replay jobs.
+// List godoc
+// @Summary List replay jobs
+// @Tags replay-jobs
+// @Accept json
+// @Produce json
+// @Param limit query int false "The limit indicates the maximum number of items to return"
+// @Param offset query int false "The offset indicates the starting position of the query in relation to the complete set of unpaginated items"
+// @Param status query string false "Filter by status field"
+// @Param created_at.gt query string false "Return results where the created_at field is greater than this value"
+// @Param created_at.gte query string false "Return results where the created_at field is greater than or equal to this value"
+// @Param created_at.lt query string false "Return results where the created_at field is less than this value"
+// @Param created_at.lte query string false "Return results where the created_at field is less than or equal to this value"
+// @Success 200 {object} replayJobList
+// @Failure 500 {object} errorResponse
+// @Router /replay-jobs [get]
+func (rj ReplayJob) List(w http.ResponseWriter, r *http.Request) {
+	listOptions := makeListOptions(r, []string{"status", "created_at.gt", "created_at.gte", "created_at.lt", "created_at.lte"})
+	listOptions.OrderBy = "created_at"
+	listOptions.Order = "desc"
+
+	// Call service
+	replayJobs, err := rj.replayJobService.List(r.Context(), listOptions)
+	if err != nil {
+		rj.logger.Error(
+			"service-error",
+			zap.String("name", "ReplayJobService"),
+			zap.String("method", "List"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, rj.logger)
+		return
+	}
+
+	// Return response
+	rl := replayJobList{
+		ReplayJobs: replayJobs,
+		Limit:      listOptions.Limit,
+		Offset:     listOptions.Offset,
+	}
+	makeJSONResponse(w, http.StatusOK, rl, rj.logger)
+}
+
+// Get replay job.
+// Get godoc
+// @Summary Show a replay job
+// @Tags replay-jobs
+// @Accept json
+// @Produce json
+// @Param replay_job_id path string true "Replay Job ID"
+// @Success 200 {object} postmand.ReplayJob
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /replay-jobs/{replay_job_id} [get]
+func (rj ReplayJob) Get(w http.ResponseWriter, r *http.Request) {
+	replayJobID, err := uuid.Parse(chi.URLParam(r, "replay_job_id"))
+	if err != nil {
+		er := errorResponses["invalid_id"]
+		makeErrorResponse(w, &er, rj.logger)
+		return
+	}
+
+	// Call service
+	getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": replayJobID}}
+	replayJob, err := rj.replayJobService.Get(r.Context(), getOptions)
+	if err != nil {
+		if err == postmand.ErrReplayJobNotFound {
+			er := errorResponses["replay_job_not_found"]
+			makeErrorResponse(w, &er, rj.logger)
+			return
+		}
+		rj.logger.Error(
+			"service-error",
+			zap.String("name", "ReplayJobService"),
+			zap.String("method", "Get"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, rj.logger)
+		return
+	}
+
+	// Return response
+	makeJSONResponse(w, http.StatusOK, replayJob, rj.logger)
+}
+
+// Create replay job.
+// Create godoc
+// @Summary Add a replay job
+// @Description The replay job is executed in background by the workers and sends again the deliveries that match the filters.
+// @Tags replay-jobs
+// @Accept json
+// @Produce json
+// @Param replay_job body postmand.ReplayJob true "Add replay job"
+// @Success 202 {object} postmand.ReplayJob
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /replay-jobs [post]
+func (rj ReplayJob) Create(w http.ResponseWriter, r *http.Request) {
+	// Parse request
+	replayJob := postmand.ReplayJob{}
+	if er := readBodyJSON(r, &replayJob, rj.logger); er != nil {
+		makeErrorResponse(w, er, rj.logger)
+		return
+	}
+
+	// Call service
+	if err := rj.replayJobService.Create(r.Context(), &replayJob); err != nil {
+		rj.logger.Error(
+			"service-error",
+			zap.String("name", "ReplayJobService"),
+			zap.String("method", "Create"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, rj.logger)
+		return
+	}
+
+	// Return response
+	makeJSONResponse(w, http.StatusAccepted, replayJob, rj.logger)
+}
+
+// NewReplayJob creates a new ReplayJob.
+func NewReplayJob(replayJobService postmand.ReplayJobService, logger *zap.Logger) *ReplayJob {
+	return &ReplayJob{
+		replayJobService: replayJobService,
+		logger:           logger,
+	}
+}
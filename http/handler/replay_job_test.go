@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/steinfletcher/apitest"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/allisson/postmand"
+	"github.com/allisson/postmand/http"
+	"github.com/allisson/postmand/mocks"
+)
+
+func makeReplayJob() postmand.ReplayJob {
+	replayJobID, _ := uuid.Parse("4fb5a4f4-2d4a-4a1e-8f55-6c6cb3ad0b0e")
+
+	return postmand.ReplayJob{
+		ID:      replayJobID,
+		Filters: postmand.ReplayJobFilters{"status": postmand.DeliveryStatusFailed},
+	}
+}
+
+func TestReplayJob(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+
+	t.Run("List", func(t *testing.T) {
+		replayJobService := &mocks.ReplayJobService{}
+		listOptions := postmand.RepositoryListOptions{Filters: map[string]interface{}{}, Limit: 50, Offset: 0, OrderBy: "created_at", Order: "desc"}
+		replayJobHandler := NewReplayJob(replayJobService, logger)
+		router := http.NewRouter(logger)
+		router.Get("/v1/replay-jobs", replayJobHandler.List)
+
+		replayJobService.On("List", mock.Anything, listOptions).Return([]*postmand.ReplayJob{{}}, nil)
+		apitest.New().
+			Handler(router).
+			Get("/v1/replay-jobs").
+			Expect(t).
+			Body(`{"replay_jobs":[{"id":"00000000-0000-0000-0000-000000000000","filters":null,"reset_delivery_attempts":false,"status":"","replayed_deliveries":0,"error":"","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}],"limit":50,"offset":0}`).
+			Status(nethttp.StatusOK).
+			End()
+
+		replayJobService.AssertExpectations(t)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		replayJobService := &mocks.ReplayJobService{}
+		replayJob := makeReplayJob()
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": replayJob.ID}}
+		replayJobHandler := NewReplayJob(replayJobService, logger)
+		router := http.NewRouter(logger)
+		router.Get("/v1/replay-jobs/{replay_job_id}", replayJobHandler.Get)
+
+		replayJobService.On("Get", mock.Anything, getOptions).Return(&replayJob, nil)
+		apitest.New().
+			Handler(router).
+			Get("/v1/replay-jobs/4fb5a4f4-2d4a-4a1e-8f55-6c6cb3ad0b0e").
+			Expect(t).
+			Body(`{"id":"4fb5a4f4-2d4a-4a1e-8f55-6c6cb3ad0b0e","filters":{"status":"failed"},"reset_delivery_attempts":false,"status":"","replayed_deliveries":0,"error":"","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`).
+			Status(nethttp.StatusOK).
+			End()
+
+		replayJobService.AssertExpectations(t)
+	})
+
+	t.Run("Get with replay job not found", func(t *testing.T) {
+		replayJobService := &mocks.ReplayJobService{}
+		replayJob := makeReplayJob()
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": replayJob.ID}}
+		replayJobHandler := NewReplayJob(replayJobService, logger)
+		router := http.NewRouter(logger)
+		router.Get("/v1/replay-jobs/{replay_job_id}", replayJobHandler.Get)
+
+		replayJobService.On("Get", mock.Anything, getOptions).Return(nil, postmand.ErrReplayJobNotFound)
+		apitest.New().
+			Handler(router).
+			Get("/v1/replay-jobs/4fb5a4f4-2d4a-4a1e-8f55-6c6cb3ad0b0e").
+			Expect(t).
+			Body(`{"code":10, "message":"replay job not found"}`).
+			Status(nethttp.StatusNotFound).
+			End()
+
+		replayJobService.AssertExpectations(t)
+	})
+
+	t.Run("Create with invalid filter", func(t *testing.T) {
+		replayJobService := &mocks.ReplayJobService{}
+		replayJobHandler := NewReplayJob(replayJobService, logger)
+		router := http.NewRouter(logger)
+		router.Post("/v1/replay-jobs", replayJobHandler.Create)
+
+		apitest.New().
+			Handler(router).
+			Post("/v1/replay-jobs").
+			JSON(`{"filters":{"payload":"{}"}}`).
+			Expect(t).
+			Body(`{"code":4, "message":"request validation failed", "details":"filters: invalid filter \"payload\"."}`).
+			Status(nethttp.StatusBadRequest).
+			End()
+
+		replayJobService.AssertExpectations(t)
+	})
+
+	t.Run("Create with valid body", func(t *testing.T) {
+		replayJobService := &mocks.ReplayJobService{}
+		replayJobHandler := NewReplayJob(replayJobService, logger)
+		replayJob := makeReplayJob()
+		jsonReplayJob, _ := json.Marshal(&replayJob)
+		router := http.NewRouter(logger)
+		router.Post("/v1/replay-jobs", replayJobHandler.Create)
+
+		replayJobService.On("Create", mock.Anything, &replayJob).Return(nil)
+		apitest.New().
+			Handler(router).
+			Post("/v1/replay-jobs").
+			JSON(jsonReplayJob).
+			Expect(t).
+			Body(`{"id":"4fb5a4f4-2d4a-4a1e-8f55-6c6cb3ad0b0e","filters":{"status":"failed"},"reset_delivery_attempts":false,"status":"","replayed_deliveries":0,"error":"","created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}`).
+			Status(nethttp.StatusAccepted).
+			End()
+
+		replayJobService.AssertExpectations(t)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -22,6 +23,8 @@ type requestFilters struct {
 	WebhookID    postmand.ID `json:"webhook_id"`
 	DeliveryID   postmand.ID `json:"delivery_id"`
 	Status       string      `json:"status"`
+	Queue        string      `json:"queue"`
+	Kind         string      `json:"kind"`
 	CreatedAtGt  time.Time   `json:"created_at.gt"`
 	CreatedAtGte time.Time   `json:"created_at.gte"`
 	CreatedAtLt  time.Time   `json:"created_at.lt"`
@@ -128,3 +131,18 @@ func makeListOptions(r *http.Request, filters []string) postmand.RepositoryListO
 
 	return listOptions
 }
+
+// payloadFilterRegex matches the filters by the fields of the JSON payload, eg: payload.order_id or payload.customer.id.
+var payloadFilterRegex = regexp.MustCompile(`^payload(\.[a-zA-Z0-9_-]+)+$`)
+
+func addPayloadFilters(r *http.Request, listOptions *postmand.RepositoryListOptions) {
+	if listOptions.Filters == nil {
+		listOptions.Filters = make(map[string]interface{})
+	}
+	for filter := range r.Form {
+		filterValue := r.Form.Get(filter)
+		if payloadFilterRegex.MatchString(filter) && filterValue != "" {
+			listOptions.Filters[filter] = filterValue
+		}
+	}
+}
@@ -16,6 +16,10 @@ type webhookList struct {
 	Offset   int                 `json:"offset"`
 } //@name WebhookList
 
+type webhookTest struct {
+	Payload string `json:"payload"`
+} //@name WebhookTest
+
 // Webhook implements rest interface for webhook.
 type Webhook struct {
 	webhookService postmand.WebhookService
@@ -31,6 +35,7 @@ type Webhook struct {
 // @Param limit query int false "The limit indicates the maximum number of items to return"
 // @Param offset query int false "The offset indicates the starting position of the query in relation to the complete set of unpaginated items"
 // @Param active query boolean false "Filter by active field"
+// @Param queue query string false "Filter by queue field"
 // @Param created_at.gt query string false "Return results where the created_at field is greater than this value"
 // @Param created_at.gte query string false "Return results where the created_at field is greater than or equal to this value"
 // @Param created_at.lt query string false "Return results where the created_at field is less than this value"
@@ -39,7 +44,7 @@ type Webhook struct {
 // @Failure 500 {object} errorResponse
 // @Router /webhooks [get]
 func (wh Webhook) List(w http.ResponseWriter, r *http.Request) {
-	listOptions := makeListOptions(r, []string{"active", "created_at.gt", "created_at.gte", "created_at.lt", "created_at.lte"})
+	listOptions := makeListOptions(r, []string{"active", "queue", "created_at.gt", "created_at.gte", "created_at.lt", "created_at.lte"})
 	listOptions.OrderBy = "name"
 	listOptions.Order = "asc"
 
@@ -224,6 +229,205 @@ func (wh Webhook) Delete(w http.ResponseWriter, r *http.Request) {
 	makeResponse(w, []byte(""), http.StatusNoContent, "application/json", wh.logger)
 }
 
+// Test webhook.
+// Test godoc
+// @Summary Send a test payload to an webhook
+// @Description The payload is sent synchronously and no delivery is created, the sample payload is used if the body does not define one.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook_id path string true "Webhook ID"
+// @Param webhook body webhookTest false "Test options"
+// @Success 200 {object} postmand.WebhookTestResult
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /webhooks/{webhook_id}/test [post]
+func (wh Webhook) Test(w http.ResponseWriter, r *http.Request) {
+	webhookID, err := uuid.Parse(chi.URLParam(r, "webhook_id"))
+	if err != nil {
+		er := errorResponses["invalid_id"]
+		makeErrorResponse(w, &er, wh.logger)
+		return
+	}
+
+	// Parse request (the body is optional)
+	wt := webhookTest{}
+	if r.ContentLength > 0 {
+		if er := readBodyJSON(r, &wt, wh.logger); er != nil {
+			makeErrorResponse(w, er, wh.logger)
+			return
+		}
+	}
+
+	// Call service
+	webhookTestResult, err := wh.webhookService.Test(r.Context(), webhookID, wt.Payload)
+	if err != nil {
+		switch err {
+		case postmand.ErrWebhookNotFound:
+			er := errorResponses["webhook_not_found"]
+			makeErrorResponse(w, &er, wh.logger)
+			return
+		case postmand.ErrWebhookNotVerified:
+			er := errorResponses["webhook_not_verified"]
+			makeErrorResponse(w, &er, wh.logger)
+			return
+		}
+		wh.logger.Error(
+			"service-error",
+			zap.String("name", "WebhookService"),
+			zap.String("method", "Test"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, wh.logger)
+		return
+	}
+
+	// Return response
+	makeJSONResponse(w, http.StatusOK, webhookTestResult, wh.logger)
+}
+
+// Verify webhook.
+// Verify godoc
+// @Summary Send again the verification challenge to an webhook
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook_id path string true "Webhook ID"
+// @Success 200 {object} postmand.Webhook
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /webhooks/{webhook_id}/verify [post]
+func (wh Webhook) Verify(w http.ResponseWriter, r *http.Request) {
+	webhookID, err := uuid.Parse(chi.URLParam(r, "webhook_id"))
+	if err != nil {
+		er := errorResponses["invalid_id"]
+		makeErrorResponse(w, &er, wh.logger)
+		return
+	}
+
+	// Call service
+	webhook, err := wh.webhookService.Verify(r.Context(), webhookID)
+	if err != nil {
+		switch err {
+		case postmand.ErrWebhookNotFound:
+			er := errorResponses["webhook_not_found"]
+			makeErrorResponse(w, &er, wh.logger)
+			return
+		case postmand.ErrWebhookVerificationFailed:
+			er := errorResponses["webhook_verification_failed"]
+			makeErrorResponse(w, &er, wh.logger)
+			return
+		}
+		wh.logger.Error(
+			"service-error",
+			zap.String("name", "WebhookService"),
+			zap.String("method", "Verify"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, wh.logger)
+		return
+	}
+
+	// Return response
+	makeJSONResponse(w, http.StatusOK, webhook, wh.logger)
+}
+
+// Preview webhook payload template.
+// Preview godoc
+// @Summary Render a payload through the payload template of an webhook
+// @Description Nothing is sent, the sample payload is used if the body does not define one.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook_id path string true "Webhook ID"
+// @Param webhook body webhookTest false "Preview options"
+// @Success 200 {object} postmand.WebhookPreview
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /webhooks/{webhook_id}/preview [post]
+func (wh Webhook) Preview(w http.ResponseWriter, r *http.Request) {
+	webhookID, err := uuid.Parse(chi.URLParam(r, "webhook_id"))
+	if err != nil {
+		er := errorResponses["invalid_id"]
+		makeErrorResponse(w, &er, wh.logger)
+		return
+	}
+
+	// Parse request (the body is optional)
+	wt := webhookTest{}
+	if r.ContentLength > 0 {
+		if er := readBodyJSON(r, &wt, wh.logger); er != nil {
+			makeErrorResponse(w, er, wh.logger)
+			return
+		}
+	}
+
+	// Call service
+	webhookPreview, err := wh.webhookService.Preview(r.Context(), webhookID, wt.Payload)
+	if err != nil {
+		if err == postmand.ErrWebhookNotFound {
+			er := errorResponses["webhook_not_found"]
+			makeErrorResponse(w, &er, wh.logger)
+			return
+		}
+		wh.logger.Error(
+			"service-error",
+			zap.String("name", "WebhookService"),
+			zap.String("method", "Preview"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, wh.logger)
+		return
+	}
+
+	// Return response
+	makeJSONResponse(w, http.StatusOK, webhookPreview, wh.logger)
+}
+
+// FilterDryRun webhook filter expression.
+// FilterDryRun godoc
+// @Summary Evaluate a filter expression against a sample payload
+// @Description Nothing is sent, use it to check which payloads a webhook with the filter expression receives.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param filter_dry_run body postmand.WebhookFilterDryRun true "Filter expression and sample payload"
+// @Success 200 {object} postmand.WebhookFilterDryRun
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /webhooks/filter-dry-run [post]
+func (wh Webhook) FilterDryRun(w http.ResponseWriter, r *http.Request) {
+	// Parse request
+	filterDryRun := postmand.WebhookFilterDryRun{}
+	if er := readBodyJSON(r, &filterDryRun, wh.logger); er != nil {
+		makeErrorResponse(w, er, wh.logger)
+		return
+	}
+
+	// Call service
+	if err := wh.webhookService.FilterDryRun(r.Context(), &filterDryRun); err != nil {
+		wh.logger.Error(
+			"service-error",
+			zap.String("name", "WebhookService"),
+			zap.String("method", "FilterDryRun"),
+			zap.Error(err),
+		)
+		er := errorResponses["internal_server_error"]
+		makeErrorResponse(w, &er, wh.logger)
+		return
+	}
+
+	// Return response
+	makeJSONResponse(w, http.StatusOK, filterDryRun, wh.logger)
+}
+
 // NewWebhook creates a new Webhook.
 func NewWebhook(webhookService postmand.WebhookService, logger *zap.Logger) *Webhook {
 	return &Webhook{
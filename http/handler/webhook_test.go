@@ -48,7 +48,7 @@ func TestWebhook(t *testing.T) {
 			Handler(router).
 			Get("/v1/webhooks").
 			Expect(t).
-			Body(`{"webhooks":[{"id":"00000000-0000-0000-0000-000000000000","name":"","url":"","content_type":"","valid_status_codes":null,"secret_token":"","active":false,"max_delivery_attempts":0,"delivery_attempt_timeout":0,"retry_min_backoff":0,"retry_max_backoff":0,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}],"limit":50,"offset":0}`).
+			Body(`{"webhooks":[{"id":"00000000-0000-0000-0000-000000000000","name":"","url":"","content_type":"","valid_status_codes":null,"secret_token":"","active":false,"max_delivery_attempts":0,"delivery_attempt_timeout":0,"retry_min_backoff":0,"retry_max_backoff":0,"priority":0,"queue":"","delivery_ttl":0,"event_types":null,"filter_expression":"","payload_template":"","payload_schema":"","delivery_format":"","compression":"","max_batch_size":0,"max_batch_wait":0,"batch_format":"","destinations":null,"destination_strategy":"","oauth2":null,"require_verification":false,"verification_status":"","verified_at":null,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"}],"limit":50,"offset":0}`).
 			Status(nethttp.StatusOK).
 			End()
 
@@ -68,7 +68,7 @@ func TestWebhook(t *testing.T) {
 			Handler(router).
 			Get("/v1/webhooks/cd9b7318-36c6-4534-be84-fe78042aeaf2").
 			Expect(t).
-			Body(`{"active":true, "content_type":"application/json", "created_at":"0001-01-01T00:00:00Z", "delivery_attempt_timeout":1, "id":"cd9b7318-36c6-4534-be84-fe78042aeaf2", "max_delivery_attempts":1, "name":"Test", "retry_max_backoff":1, "retry_min_backoff":1, "secret_token":"", "updated_at":"0001-01-01T00:00:00Z", "url":"https://httpbin.org/post", "valid_status_codes":[200, 201]}`).
+			Body(`{"active":true, "content_type":"application/json", "created_at":"0001-01-01T00:00:00Z", "delivery_attempt_timeout":1, "id":"cd9b7318-36c6-4534-be84-fe78042aeaf2", "max_delivery_attempts":1, "name":"Test", "priority":0, "queue":"", "delivery_ttl":0, "event_types":null, "filter_expression":"", "payload_template":"", "payload_schema":"", "delivery_format":"","compression":"", "max_batch_size":0, "max_batch_wait":0, "batch_format":"", "destinations":null, "destination_strategy":"", "oauth2":null, "require_verification":false, "verification_status":"", "verified_at":null, "retry_max_backoff":1, "retry_min_backoff":1, "secret_token":"", "updated_at":"0001-01-01T00:00:00Z", "url":"https://httpbin.org/post", "valid_status_codes":[200, 201]}`).
 			Status(nethttp.StatusOK).
 			End()
 
@@ -125,7 +125,7 @@ func TestWebhook(t *testing.T) {
 			Post("/v1/webhooks").
 			JSON(jsonWebhook).
 			Expect(t).
-			Body(`{"active":true, "content_type":"application/json", "created_at":"0001-01-01T00:00:00Z", "delivery_attempt_timeout":1, "id":"cd9b7318-36c6-4534-be84-fe78042aeaf2", "max_delivery_attempts":1, "name":"Test", "retry_max_backoff":1, "retry_min_backoff":1, "secret_token":"", "updated_at":"0001-01-01T00:00:00Z", "url":"https://httpbin.org/post", "valid_status_codes": [200, 201]}`).
+			Body(`{"active":true, "content_type":"application/json", "created_at":"0001-01-01T00:00:00Z", "delivery_attempt_timeout":1, "id":"cd9b7318-36c6-4534-be84-fe78042aeaf2", "max_delivery_attempts":1, "name":"Test", "priority":0, "queue":"", "delivery_ttl":0, "event_types":null, "filter_expression":"", "payload_template":"", "payload_schema":"", "delivery_format":"","compression":"", "max_batch_size":0, "max_batch_wait":0, "batch_format":"", "destinations":null, "destination_strategy":"", "oauth2":null, "require_verification":false, "verification_status":"", "verified_at":null, "retry_max_backoff":1, "retry_min_backoff":1, "secret_token":"", "updated_at":"0001-01-01T00:00:00Z", "url":"https://httpbin.org/post", "valid_status_codes": [200, 201]}`).
 			Status(nethttp.StatusCreated).
 			End()
 
@@ -182,7 +182,7 @@ func TestWebhook(t *testing.T) {
 			Put("/v1/webhooks/cd9b7318-36c6-4534-be84-fe78042aeaf2").
 			JSON(jsonWebhook).
 			Expect(t).
-			Body(`{"active":true, "content_type":"application/json", "created_at":"0001-01-01T00:00:00Z", "delivery_attempt_timeout":1, "id":"cd9b7318-36c6-4534-be84-fe78042aeaf2", "max_delivery_attempts":1, "name":"Test", "retry_max_backoff":1, "retry_min_backoff":1, "secret_token":"", "updated_at":"0001-01-01T00:00:00Z", "url":"https://httpbin.org/post", "valid_status_codes":[200, 201]}`).
+			Body(`{"active":true, "content_type":"application/json", "created_at":"0001-01-01T00:00:00Z", "delivery_attempt_timeout":1, "id":"cd9b7318-36c6-4534-be84-fe78042aeaf2", "max_delivery_attempts":1, "name":"Test", "priority":0, "queue":"", "delivery_ttl":0, "event_types":null, "filter_expression":"", "payload_template":"", "payload_schema":"", "delivery_format":"","compression":"", "max_batch_size":0, "max_batch_wait":0, "batch_format":"", "destinations":null, "destination_strategy":"", "oauth2":null, "require_verification":false, "verification_status":"", "verified_at":null, "retry_max_backoff":1, "retry_min_backoff":1, "secret_token":"", "updated_at":"0001-01-01T00:00:00Z", "url":"https://httpbin.org/post", "valid_status_codes":[200, 201]}`).
 			Status(nethttp.StatusOK).
 			End()
 
@@ -206,4 +206,195 @@ func TestWebhook(t *testing.T) {
 
 		webhookService.AssertExpectations(t)
 	})
+
+	t.Run("Test", func(t *testing.T) {
+		webhookService := &mocks.WebhookService{}
+		webhookHandler := NewWebhook(webhookService, logger)
+		webhook := makeWebhook()
+		webhookTestResult := postmand.WebhookTestResult{
+			WebhookID:          webhook.ID,
+			URL:                webhook.URL,
+			Payload:            `{"id": 1}`,
+			ResponseStatusCode: 200,
+			ExecutionDuration:  10,
+			Success:            true,
+		}
+		router := http.NewRouter(logger)
+		router.Post("/v1/webhooks/{webhook_id}/test", webhookHandler.Test)
+
+		webhookService.On("Test", mock.Anything, webhook.ID, `{"id": 1}`).Return(&webhookTestResult, nil)
+		apitest.New().
+			Handler(router).
+			Post("/v1/webhooks/cd9b7318-36c6-4534-be84-fe78042aeaf2/test").
+			JSON(`{"payload":"{\"id\": 1}"}`).
+			Expect(t).
+			Body(`{"webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2","url":"https://httpbin.org/post","payload":"{\"id\": 1}","raw_request":"","raw_response":"","response_status_code":200,"execution_duration":10,"success":true,"error":""}`).
+			Status(nethttp.StatusOK).
+			End()
+
+		webhookService.AssertExpectations(t)
+	})
+
+	t.Run("Test with webhook not found", func(t *testing.T) {
+		webhookService := &mocks.WebhookService{}
+		webhookHandler := NewWebhook(webhookService, logger)
+		webhook := makeWebhook()
+		router := http.NewRouter(logger)
+		router.Post("/v1/webhooks/{webhook_id}/test", webhookHandler.Test)
+
+		webhookService.On("Test", mock.Anything, webhook.ID, "").Return(nil, postmand.ErrWebhookNotFound)
+		apitest.New().
+			Handler(router).
+			Post("/v1/webhooks/cd9b7318-36c6-4534-be84-fe78042aeaf2/test").
+			Expect(t).
+			Body(`{"code":5, "message":"webhook not found"}`).
+			Status(nethttp.StatusNotFound).
+			End()
+
+		webhookService.AssertExpectations(t)
+	})
+
+	t.Run("Test with webhook not verified", func(t *testing.T) {
+		webhookService := &mocks.WebhookService{}
+		webhookHandler := NewWebhook(webhookService, logger)
+		webhook := makeWebhook()
+		router := http.NewRouter(logger)
+		router.Post("/v1/webhooks/{webhook_id}/test", webhookHandler.Test)
+
+		webhookService.On("Test", mock.Anything, webhook.ID, "").Return(nil, postmand.ErrWebhookNotVerified)
+		apitest.New().
+			Handler(router).
+			Post("/v1/webhooks/cd9b7318-36c6-4534-be84-fe78042aeaf2/test").
+			Expect(t).
+			Body(`{"code":14, "message":"webhook not verified"}`).
+			Status(nethttp.StatusConflict).
+			End()
+
+		webhookService.AssertExpectations(t)
+	})
+
+	t.Run("Verify", func(t *testing.T) {
+		webhookService := &mocks.WebhookService{}
+		webhookHandler := NewWebhook(webhookService, logger)
+		webhook := makeWebhook()
+		webhook.RequireVerification = true
+		webhook.VerificationStatus = postmand.WebhookVerificationStatusVerified
+		webhook.VerificationToken = "token"
+		router := http.NewRouter(logger)
+		router.Post("/v1/webhooks/{webhook_id}/verify", webhookHandler.Verify)
+
+		webhookService.On("Verify", mock.Anything, webhook.ID).Return(&webhook, nil)
+		apitest.New().
+			Handler(router).
+			Post("/v1/webhooks/cd9b7318-36c6-4534-be84-fe78042aeaf2/verify").
+			Expect(t).
+			Body(`{"active":true, "content_type":"application/json", "created_at":"0001-01-01T00:00:00Z", "delivery_attempt_timeout":1, "id":"cd9b7318-36c6-4534-be84-fe78042aeaf2", "max_delivery_attempts":1, "name":"Test", "priority":0, "queue":"", "delivery_ttl":0, "event_types":null, "filter_expression":"", "payload_template":"", "payload_schema":"", "delivery_format":"","compression":"", "max_batch_size":0, "max_batch_wait":0, "batch_format":"", "destinations":null, "destination_strategy":"", "oauth2":null, "require_verification":true, "verification_status":"verified", "verified_at":null, "retry_max_backoff":1, "retry_min_backoff":1, "secret_token":"", "updated_at":"0001-01-01T00:00:00Z", "url":"https://httpbin.org/post", "valid_status_codes":[200, 201]}`).
+			Status(nethttp.StatusOK).
+			End()
+
+		webhookService.AssertExpectations(t)
+	})
+
+	t.Run("Verify with verification failed", func(t *testing.T) {
+		webhookService := &mocks.WebhookService{}
+		webhookHandler := NewWebhook(webhookService, logger)
+		webhook := makeWebhook()
+		router := http.NewRouter(logger)
+		router.Post("/v1/webhooks/{webhook_id}/verify", webhookHandler.Verify)
+
+		webhookService.On("Verify", mock.Anything, webhook.ID).Return(&webhook, postmand.ErrWebhookVerificationFailed)
+		apitest.New().
+			Handler(router).
+			Post("/v1/webhooks/cd9b7318-36c6-4534-be84-fe78042aeaf2/verify").
+			Expect(t).
+			Body(`{"code":12, "message":"webhook verification failed"}`).
+			Status(nethttp.StatusConflict).
+			End()
+
+		webhookService.AssertExpectations(t)
+	})
+
+	t.Run("Preview", func(t *testing.T) {
+		webhookService := &mocks.WebhookService{}
+		webhookHandler := NewWebhook(webhookService, logger)
+		webhook := makeWebhook()
+		webhookPreview := postmand.WebhookPreview{
+			WebhookID:       webhook.ID,
+			Payload:         `{"id": 1}`,
+			RenderedPayload: `{"text": 1}`,
+		}
+		router := http.NewRouter(logger)
+		router.Post("/v1/webhooks/{webhook_id}/preview", webhookHandler.Preview)
+
+		webhookService.On("Preview", mock.Anything, webhook.ID, `{"id": 1}`).Return(&webhookPreview, nil)
+		apitest.New().
+			Handler(router).
+			Post("/v1/webhooks/cd9b7318-36c6-4534-be84-fe78042aeaf2/preview").
+			JSON(`{"payload":"{\"id\": 1}"}`).
+			Expect(t).
+			Body(`{"webhook_id":"cd9b7318-36c6-4534-be84-fe78042aeaf2","payload":"{\"id\": 1}","rendered_payload":"{\"text\": 1}","error":""}`).
+			Status(nethttp.StatusOK).
+			End()
+
+		webhookService.AssertExpectations(t)
+	})
+
+	t.Run("Preview with webhook not found", func(t *testing.T) {
+		webhookService := &mocks.WebhookService{}
+		webhookHandler := NewWebhook(webhookService, logger)
+		webhook := makeWebhook()
+		router := http.NewRouter(logger)
+		router.Post("/v1/webhooks/{webhook_id}/preview", webhookHandler.Preview)
+
+		webhookService.On("Preview", mock.Anything, webhook.ID, "").Return(nil, postmand.ErrWebhookNotFound)
+		apitest.New().
+			Handler(router).
+			Post("/v1/webhooks/cd9b7318-36c6-4534-be84-fe78042aeaf2/preview").
+			Expect(t).
+			Body(`{"code":5, "message":"webhook not found"}`).
+			Status(nethttp.StatusNotFound).
+			End()
+
+		webhookService.AssertExpectations(t)
+	})
+
+	t.Run("FilterDryRun", func(t *testing.T) {
+		webhookService := &mocks.WebhookService{}
+		webhookHandler := NewWebhook(webhookService, logger)
+		filterDryRun := postmand.WebhookFilterDryRun{FilterExpression: `payload.country == "BR"`, Payload: `{"country": "BR"}`}
+		router := http.NewRouter(logger)
+		router.Post("/v1/webhooks/filter-dry-run", webhookHandler.FilterDryRun)
+
+		webhookService.On("FilterDryRun", mock.Anything, &filterDryRun).Run(func(args mock.Arguments) {
+			args.Get(1).(*postmand.WebhookFilterDryRun).Matched = true
+		}).Return(nil)
+		apitest.New().
+			Handler(router).
+			Post("/v1/webhooks/filter-dry-run").
+			JSON(`{"filter_expression":"payload.country == \"BR\"","payload":"{\"country\": \"BR\"}"}`).
+			Expect(t).
+			Body(`{"filter_expression":"payload.country == \"BR\"","payload":"{\"country\": \"BR\"}","matched":true}`).
+			Status(nethttp.StatusOK).
+			End()
+
+		webhookService.AssertExpectations(t)
+	})
+
+	t.Run("FilterDryRun with invalid filter expression", func(t *testing.T) {
+		webhookService := &mocks.WebhookService{}
+		webhookHandler := NewWebhook(webhookService, logger)
+		router := http.NewRouter(logger)
+		router.Post("/v1/webhooks/filter-dry-run", webhookHandler.FilterDryRun)
+
+		apitest.New().
+			Handler(router).
+			Post("/v1/webhooks/filter-dry-run").
+			JSON(`{"filter_expression":"payload.country ==","payload":"{}"}`).
+			Expect(t).
+			Body(`{"code":4, "message":"request validation failed", "details":"filter_expression: unexpected end of expression."}`).
+			Status(nethttp.StatusBadRequest).
+			End()
+
+		webhookService.AssertExpectations(t)
+	})
 }
@@ -16,6 +16,29 @@ type DeliveryRepository struct {
 	mock.Mock
 }
 
+// Cancel provides a mock function with given fields: ctx, id
+func (_m *DeliveryRepository) Cancel(ctx context.Context, id uuid.UUID) (*postmand.Delivery, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *postmand.Delivery
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *postmand.Delivery); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*postmand.Delivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, delivery
 func (_m *DeliveryRepository) Create(ctx context.Context, delivery *postmand.Delivery) error {
 	ret := _m.Called(ctx, delivery)
@@ -44,13 +67,13 @@ func (_m *DeliveryRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r0
 }
 
-// Dispatch provides a mock function with given fields: ctx
-func (_m *DeliveryRepository) Dispatch(ctx context.Context) (*postmand.DeliveryAttempt, error) {
-	ret := _m.Called(ctx)
+// Dispatch provides a mock function with given fields: ctx, dispatchOptions
+func (_m *DeliveryRepository) Dispatch(ctx context.Context, dispatchOptions postmand.RepositoryDispatchOptions) (*postmand.DeliveryAttempt, error) {
+	ret := _m.Called(ctx, dispatchOptions)
 
 	var r0 *postmand.DeliveryAttempt
-	if rf, ok := ret.Get(0).(func(context.Context) *postmand.DeliveryAttempt); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, postmand.RepositoryDispatchOptions) *postmand.DeliveryAttempt); ok {
+		r0 = rf(ctx, dispatchOptions)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*postmand.DeliveryAttempt)
@@ -58,8 +81,29 @@ func (_m *DeliveryRepository) Dispatch(ctx context.Context) (*postmand.DeliveryA
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, postmand.RepositoryDispatchOptions) error); ok {
+		r1 = rf(ctx, dispatchOptions)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Expire provides a mock function with given fields: ctx, dispatchOptions
+func (_m *DeliveryRepository) Expire(ctx context.Context, dispatchOptions postmand.RepositoryDispatchOptions) (int, error) {
+	ret := _m.Called(ctx, dispatchOptions)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, postmand.RepositoryDispatchOptions) int); ok {
+		r0 = rf(ctx, dispatchOptions)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, postmand.RepositoryDispatchOptions) error); ok {
+		r1 = rf(ctx, dispatchOptions)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -113,6 +157,66 @@ func (_m *DeliveryRepository) List(ctx context.Context, listOptions postmand.Rep
 	return r0, r1
 }
 
+// ReleaseIdempotencyKey provides a mock function with given fields: ctx, id
+func (_m *DeliveryRepository) ReleaseIdempotencyKey(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplayToURL provides a mock function with given fields: ctx, id, url
+func (_m *DeliveryRepository) ReplayToURL(ctx context.Context, id uuid.UUID, url string) (*postmand.DeliveryAttempt, error) {
+	ret := _m.Called(ctx, id, url)
+
+	var r0 *postmand.DeliveryAttempt
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) *postmand.DeliveryAttempt); ok {
+		r0 = rf(ctx, id, url)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*postmand.DeliveryAttempt)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, id, url)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Retry provides a mock function with given fields: ctx, id, resetDeliveryAttempts
+func (_m *DeliveryRepository) Retry(ctx context.Context, id uuid.UUID, resetDeliveryAttempts bool) (*postmand.Delivery, error) {
+	ret := _m.Called(ctx, id, resetDeliveryAttempts)
+
+	var r0 *postmand.Delivery
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, bool) *postmand.Delivery); ok {
+		r0 = rf(ctx, id, resetDeliveryAttempts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*postmand.Delivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, bool) error); ok {
+		r1 = rf(ctx, id, resetDeliveryAttempts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, delivery
 func (_m *DeliveryRepository) Update(ctx context.Context, delivery *postmand.Delivery) error {
 	ret := _m.Called(ctx, delivery)
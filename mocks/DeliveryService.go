@@ -16,18 +16,48 @@ type DeliveryService struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: ctx, delivery
-func (_m *DeliveryService) Create(ctx context.Context, delivery *postmand.Delivery) error {
-	ret := _m.Called(ctx, delivery)
+// Cancel provides a mock function with given fields: ctx, id
+func (_m *DeliveryService) Cancel(ctx context.Context, id uuid.UUID) (*postmand.Delivery, error) {
+	ret := _m.Called(ctx, id)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *postmand.Delivery) error); ok {
-		r0 = rf(ctx, delivery)
+	var r0 *postmand.Delivery
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *postmand.Delivery); ok {
+		r0 = rf(ctx, id)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*postmand.Delivery)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, delivery, useWebhookPriority
+func (_m *DeliveryService) Create(ctx context.Context, delivery *postmand.Delivery, useWebhookPriority bool) (bool, error) {
+	ret := _m.Called(ctx, delivery, useWebhookPriority)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, *postmand.Delivery, bool) bool); ok {
+		r0 = rf(ctx, delivery, useWebhookPriority)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *postmand.Delivery, bool) error); ok {
+		r1 = rf(ctx, delivery, useWebhookPriority)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // Delete provides a mock function with given fields: ctx, id
@@ -90,6 +120,52 @@ func (_m *DeliveryService) List(ctx context.Context, listOptions postmand.Reposi
 	return r0, r1
 }
 
+// ReplayToURL provides a mock function with given fields: ctx, id, url
+func (_m *DeliveryService) ReplayToURL(ctx context.Context, id uuid.UUID, url string) (*postmand.DeliveryAttempt, error) {
+	ret := _m.Called(ctx, id, url)
+
+	var r0 *postmand.DeliveryAttempt
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) *postmand.DeliveryAttempt); ok {
+		r0 = rf(ctx, id, url)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*postmand.DeliveryAttempt)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, id, url)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Retry provides a mock function with given fields: ctx, id, resetDeliveryAttempts
+func (_m *DeliveryService) Retry(ctx context.Context, id uuid.UUID, resetDeliveryAttempts bool) (*postmand.Delivery, error) {
+	ret := _m.Called(ctx, id, resetDeliveryAttempts)
+
+	var r0 *postmand.Delivery
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, bool) *postmand.Delivery); ok {
+		r0 = rf(ctx, id, resetDeliveryAttempts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*postmand.Delivery)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, bool) error); ok {
+		r1 = rf(ctx, id, resetDeliveryAttempts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, delivery
 func (_m *DeliveryService) Update(ctx context.Context, delivery *postmand.Delivery) error {
 	ret := _m.Called(ctx, delivery)
@@ -13,6 +13,27 @@ type MigrationRepository struct {
 	mock.Mock
 }
 
+// BackfillPayloadJSON provides a mock function with given fields: ctx, batchSize
+func (_m *MigrationRepository) BackfillPayloadJSON(ctx context.Context, batchSize int) (int, error) {
+	ret := _m.Called(ctx, batchSize)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, int) int); ok {
+		r0 = rf(ctx, batchSize)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, batchSize)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Run provides a mock function with given fields: ctx
 func (_m *MigrationRepository) Run(ctx context.Context) error {
 	ret := _m.Called(ctx)
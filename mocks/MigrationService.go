@@ -13,6 +13,20 @@ type MigrationService struct {
 	mock.Mock
 }
 
+// BackfillPayloadJSON provides a mock function with given fields: ctx, batchSize
+func (_m *MigrationService) BackfillPayloadJSON(ctx context.Context, batchSize int) error {
+	ret := _m.Called(ctx, batchSize)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, batchSize)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Run provides a mock function with given fields: ctx
 func (_m *MigrationService) Run(ctx context.Context) error {
 	ret := _m.Called(ctx)
@@ -90,6 +90,52 @@ func (_m *WebhookRepository) List(ctx context.Context, listOptions postmand.Repo
 	return r0, r1
 }
 
+// ListSubscribed provides a mock function with given fields: ctx, eventType
+func (_m *WebhookRepository) ListSubscribed(ctx context.Context, eventType string) ([]*postmand.Webhook, error) {
+	ret := _m.Called(ctx, eventType)
+
+	var r0 []*postmand.Webhook
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*postmand.Webhook); ok {
+		r0 = rf(ctx, eventType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*postmand.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, eventType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Test provides a mock function with given fields: ctx, id, payload
+func (_m *WebhookRepository) Test(ctx context.Context, id uuid.UUID, payload string) (*postmand.WebhookTestResult, error) {
+	ret := _m.Called(ctx, id, payload)
+
+	var r0 *postmand.WebhookTestResult
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) *postmand.WebhookTestResult); ok {
+		r0 = rf(ctx, id, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*postmand.WebhookTestResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, id, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, webhook
 func (_m *WebhookRepository) Update(ctx context.Context, webhook *postmand.Webhook) error {
 	ret := _m.Called(ctx, webhook)
@@ -103,3 +149,26 @@ func (_m *WebhookRepository) Update(ctx context.Context, webhook *postmand.Webho
 
 	return r0
 }
+
+// Verify provides a mock function with given fields: ctx, id
+func (_m *WebhookRepository) Verify(ctx context.Context, id uuid.UUID) (*postmand.Webhook, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *postmand.Webhook
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *postmand.Webhook); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*postmand.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -44,6 +44,20 @@ func (_m *WebhookService) Delete(ctx context.Context, id uuid.UUID) error {
 	return r0
 }
 
+// FilterDryRun provides a mock function with given fields: ctx, filterDryRun
+func (_m *WebhookService) FilterDryRun(ctx context.Context, filterDryRun *postmand.WebhookFilterDryRun) error {
+	ret := _m.Called(ctx, filterDryRun)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *postmand.WebhookFilterDryRun) error); ok {
+		r0 = rf(ctx, filterDryRun)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Get provides a mock function with given fields: ctx, getOptions
 func (_m *WebhookService) Get(ctx context.Context, getOptions postmand.RepositoryGetOptions) (*postmand.Webhook, error) {
 	ret := _m.Called(ctx, getOptions)
@@ -90,6 +104,52 @@ func (_m *WebhookService) List(ctx context.Context, listOptions postmand.Reposit
 	return r0, r1
 }
 
+// Preview provides a mock function with given fields: ctx, id, payload
+func (_m *WebhookService) Preview(ctx context.Context, id uuid.UUID, payload string) (*postmand.WebhookPreview, error) {
+	ret := _m.Called(ctx, id, payload)
+
+	var r0 *postmand.WebhookPreview
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) *postmand.WebhookPreview); ok {
+		r0 = rf(ctx, id, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*postmand.WebhookPreview)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, id, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Test provides a mock function with given fields: ctx, id, payload
+func (_m *WebhookService) Test(ctx context.Context, id uuid.UUID, payload string) (*postmand.WebhookTestResult, error) {
+	ret := _m.Called(ctx, id, payload)
+
+	var r0 *postmand.WebhookTestResult
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) *postmand.WebhookTestResult); ok {
+		r0 = rf(ctx, id, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*postmand.WebhookTestResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, id, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, webhook
 func (_m *WebhookService) Update(ctx context.Context, webhook *postmand.Webhook) error {
 	ret := _m.Called(ctx, webhook)
@@ -103,3 +163,26 @@ func (_m *WebhookService) Update(ctx context.Context, webhook *postmand.Webhook)
 
 	return r0
 }
+
+// Verify provides a mock function with given fields: ctx, id
+func (_m *WebhookService) Verify(ctx context.Context, id uuid.UUID) (*postmand.Webhook, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *postmand.Webhook
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *postmand.Webhook); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*postmand.Webhook)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
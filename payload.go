@@ -0,0 +1,107 @@
+package postmand
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// payloadFieldName is the field name used for the payload itself in the validation errors.
+const payloadFieldName = "payload"
+
+// PayloadValidationError is returned when a payload does not conform to the webhook content type, the max payload size
+// or the webhook payload schema, it contains an error for each invalid field, eg: payload.customer.id or payload.items[0].sku.
+type PayloadValidationError struct {
+	Errors validation.Errors
+}
+
+func (e *PayloadValidationError) Error() string {
+	return e.Errors.Error()
+}
+
+func newPayloadValidationError(err error) *PayloadValidationError {
+	return &PayloadValidationError{Errors: validation.Errors{payloadFieldName: err}}
+}
+
+// payloadFormat returns the payload format declared by the content type: json, xml, form or an empty string for the
+// formats that are not validated, the structured syntax suffixes are supported (application/vnd.api+json).
+func payloadFormat(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return "json"
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return "xml"
+	case mediaType == "application/x-www-form-urlencoded":
+		return "form"
+	}
+	return ""
+}
+
+func validXML(payload string) bool {
+	decoder := xml.NewDecoder(strings.NewReader(payload))
+	elements := 0
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return elements > 0
+		}
+		if err != nil {
+			return false
+		}
+		if _, ok := token.(xml.StartElement); ok {
+			elements++
+		}
+	}
+}
+
+// ValidatePayloadContentType returns a *PayloadValidationError if the payload is not valid for the content type,
+// JSON, XML and form-urlencoded content types are validated and the other ones accept any payload.
+func ValidatePayloadContentType(contentType, payload string) error {
+	switch payloadFormat(contentType) {
+	case "json":
+		if !json.Valid([]byte(payload)) {
+			return newPayloadValidationError(errors.New("must be a valid JSON"))
+		}
+	case "xml":
+		if !validXML(payload) {
+			return newPayloadValidationError(errors.New("must be a valid XML"))
+		}
+	case "form":
+		if _, err := url.ParseQuery(payload); err != nil {
+			return newPayloadValidationError(errors.New("must be a valid form-urlencoded"))
+		}
+	}
+	return nil
+}
+
+// ValidatePayloadSize returns a *PayloadValidationError if the payload is larger than maxSize bytes, zero means no limit.
+func ValidatePayloadSize(payload string, maxSize int) error {
+	if maxSize > 0 && len(payload) > maxSize {
+		return newPayloadValidationError(fmt.Errorf("the size must be no more than %d bytes", maxSize))
+	}
+	return nil
+}
+
+// RenderPayload returns the body sent to the webhook, the payload transformed by the payload template or the payload itself
+// when the template is empty. Returns a *PayloadValidationError if the template can't be rendered with the payload.
+func RenderPayload(payloadTemplate, payload string) (string, error) {
+	if payloadTemplate == "" {
+		return payload, nil
+	}
+	renderedPayload, err := RenderPayloadTemplate(payloadTemplate, payload)
+	if err != nil {
+		return "", newPayloadValidationError(fmt.Errorf("cannot be rendered by the payload template: %v", err))
+	}
+	return renderedPayload, nil
+}
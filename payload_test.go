@@ -0,0 +1,62 @@
+package postmand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePayloadContentType(t *testing.T) {
+	var tests = []struct {
+		kind          string
+		contentType   string
+		payload       string
+		expectedError string
+	}{
+		{"Valid JSON", "application/json", `{"id": 1}`, ""},
+		{"Valid JSON with parameters", "application/json; charset=utf-8", `[1, 2]`, ""},
+		{"Valid JSON suffix", "application/vnd.api+json", `{"data": null}`, ""},
+		{"Invalid JSON", "application/json", `id=1`, "payload: must be a valid JSON."},
+		{"Valid XML", "application/xml", `<?xml version="1.0"?><order><id>1</id></order>`, ""},
+		{"Valid XML suffix", "application/atom+xml", `<feed></feed>`, ""},
+		{"Invalid XML", "text/xml", `<order><id>1</order>`, "payload: must be a valid XML."},
+		{"XML without elements", "application/xml", `order`, "payload: must be a valid XML."},
+		{"Valid form", "application/x-www-form-urlencoded", `id=1&name=order+1`, ""},
+		{"Invalid form", "application/x-www-form-urlencoded", `id=%zz`, "payload: must be a valid form-urlencoded."},
+		{"Not validated content type", "text/plain", `{`, ""},
+		{"Invalid content type", "application/", `{`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			err := ValidatePayloadContentType(tt.contentType, tt.payload)
+			if tt.expectedError == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.IsType(t, &PayloadValidationError{}, err)
+			assert.Equal(t, tt.expectedError, err.Error())
+		})
+	}
+}
+
+func TestValidatePayloadSize(t *testing.T) {
+	assert.Nil(t, ValidatePayloadSize(`{"id": 1}`, 9))
+	assert.Nil(t, ValidatePayloadSize(`{"id": 10}`, 0))
+	err := ValidatePayloadSize(`{"id": 10}`, 9)
+	assert.IsType(t, &PayloadValidationError{}, err)
+	assert.Equal(t, "payload: the size must be no more than 9 bytes.", err.Error())
+}
+
+func TestRenderPayload(t *testing.T) {
+	payload, err := RenderPayload("", `{"id": 1}`)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"id": 1}`, payload)
+
+	payload, err = RenderPayload(`<order><id>{{ .id }}</id></order>`, `{"id": 1}`)
+	assert.Nil(t, err)
+	assert.Equal(t, `<order><id>1</id></order>`, payload)
+
+	_, err = RenderPayload(`<order><id>{{ .id }}</id></order>`, `{"order_id": 1}`)
+	assert.IsType(t, &PayloadValidationError{}, err)
+	assert.Contains(t, err.Error(), "payload: cannot be rendered by the payload template:")
+}
@@ -16,13 +16,21 @@ type RepositoryListOptions struct {
 	Order   string
 }
 
+// RepositoryDispatchOptions contains options used in the Dispatch method.
+type RepositoryDispatchOptions struct {
+	Queues []string
+}
+
 // WebhookRepository is the interface that will be used to iterate with the Webhook data.
 type WebhookRepository interface {
 	Get(ctx context.Context, getOptions RepositoryGetOptions) (*Webhook, error)
 	List(ctx context.Context, listOptions RepositoryListOptions) ([]*Webhook, error)
+	ListSubscribed(ctx context.Context, eventType string) ([]*Webhook, error)
 	Create(ctx context.Context, webhook *Webhook) error
 	Update(ctx context.Context, webhook *Webhook) error
 	Delete(ctx context.Context, id ID) error
+	Test(ctx context.Context, id ID, payload string) (*WebhookTestResult, error)
+	Verify(ctx context.Context, id ID) (*Webhook, error)
 }
 
 // DeliveryRepository is the interface that will be used to iterate with the Delivery data.
@@ -32,7 +40,12 @@ type DeliveryRepository interface {
 	Create(ctx context.Context, delivery *Delivery) error
 	Update(ctx context.Context, delivery *Delivery) error
 	Delete(ctx context.Context, id ID) error
-	Dispatch(ctx context.Context) (*DeliveryAttempt, error)
+	Cancel(ctx context.Context, id ID) (*Delivery, error)
+	Retry(ctx context.Context, id ID, resetDeliveryAttempts bool) (*Delivery, error)
+	Dispatch(ctx context.Context, dispatchOptions RepositoryDispatchOptions) (*DeliveryAttempt, error)
+	Expire(ctx context.Context, dispatchOptions RepositoryDispatchOptions) (int, error)
+	ReplayToURL(ctx context.Context, id ID, url string) (*DeliveryAttempt, error)
+	ReleaseIdempotencyKey(ctx context.Context, id ID) error
 }
 
 // DeliveryAttemptRepository is the interface that will be used to iterate with the DeliveryAttempt data.
@@ -42,9 +55,34 @@ type DeliveryAttemptRepository interface {
 	Create(ctx context.Context, deliveryAttempt *DeliveryAttempt) error
 }
 
+// ReplayJobRepository is the interface that will be used to iterate with the ReplayJob data.
+type ReplayJobRepository interface {
+	Get(ctx context.Context, getOptions RepositoryGetOptions) (*ReplayJob, error)
+	List(ctx context.Context, listOptions RepositoryListOptions) ([]*ReplayJob, error)
+	Create(ctx context.Context, replayJob *ReplayJob) error
+	Run(ctx context.Context, id ID) (*ReplayJob, error)
+}
+
+// EventRepository is the interface that will be used to iterate with the Event data.
+type EventRepository interface {
+	Get(ctx context.Context, getOptions RepositoryGetOptions) (*Event, error)
+	List(ctx context.Context, listOptions RepositoryListOptions) ([]*Event, error)
+	Create(ctx context.Context, event *Event, deliveries []*Delivery) error
+}
+
+// DeadLetterRepository is the interface that will be used to iterate with the failed Delivery data.
+type DeadLetterRepository interface {
+	Get(ctx context.Context, getOptions RepositoryGetOptions) (*DeadLetter, error)
+	List(ctx context.Context, listOptions RepositoryListOptions) ([]*DeadLetter, error)
+	Stats(ctx context.Context, listOptions RepositoryListOptions) ([]*DeadLetterStats, error)
+	Replay(ctx context.Context, id ID, resetDeliveryAttempts bool) (*Delivery, error)
+	Discard(ctx context.Context, id ID) (*Delivery, error)
+}
+
 // MigrationRepository is the interface that will be used to run database migrations.
 type MigrationRepository interface {
 	Run(ctx context.Context) error
+	BackfillPayloadJSON(ctx context.Context, batchSize int) (int, error)
 }
 
 // PingRepository is the interface that will be used to run ping against database.
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/allisson/postmand"
+)
+
+const (
+	// batchJSONContentType is the content type of the batches sent as a JSON array
+	batchJSONContentType = "application/json"
+	// batchNDJSONContentType is the content type of the batches sent as newline delimited JSON
+	batchNDJSONContentType = "application/x-ndjson"
+	// cloudEventsBatchContentType is the content type of the CloudEvents batched JSON mode
+	cloudEventsBatchContentType = "application/cloudevents-batch+json"
+)
+
+// batchElement returns the delivery as a single line JSON value: the CloudEvents envelope in the structured mode,
+// the payload if it is a valid JSON, a base64 string for binary payloads or a JSON string for the other payloads.
+func batchElement(webhook *postmand.Webhook, delivery *postmand.Delivery, payload string) (string, error) {
+	if webhook.DeliveryFormat == postmand.WebhookDeliveryFormatCloudEventsStructured {
+		body, _, err := formatRequest(webhook, delivery, payload)
+		return body, err
+	}
+	if json.Valid([]byte(payload)) {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, []byte(payload)); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+	value := payload
+	if !utf8.ValidString(payload) {
+		value = base64.StdEncoding.EncodeToString([]byte(payload))
+	}
+	element, err := json.Marshal(value)
+	return string(element), err
+}
+
+// formatBatchRequest returns the body and the headers of a request that contains several deliveries.
+func formatBatchRequest(webhook *postmand.Webhook, deliveries []*postmand.Delivery) (string, map[string]string, error) {
+	elements := make([]string, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		payload, err := renderPayload(webhook, delivery)
+		if err != nil {
+			return "", nil, fmt.Errorf("delivery %s: %v", delivery.ID, err)
+		}
+		element, err := batchElement(webhook, delivery, payload)
+		if err != nil {
+			return "", nil, fmt.Errorf("delivery %s: %v", delivery.ID, err)
+		}
+		elements = append(elements, element)
+	}
+
+	if webhook.BatchFormat == postmand.WebhookBatchFormatNDJSON {
+		return strings.Join(elements, "\n") + "\n", map[string]string{"Content-Type": batchNDJSONContentType}, nil
+	}
+	contentType := batchJSONContentType
+	if webhook.DeliveryFormat == postmand.WebhookDeliveryFormatCloudEventsStructured {
+		contentType = cloudEventsBatchContentType
+	}
+	return "[" + strings.Join(elements, ",") + "]", map[string]string{"Content-Type": contentType}, nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/allisson/postmand"
+)
+
+func TestFormatBatchRequest(t *testing.T) {
+	webhookID, _ := uuid.Parse("cd9b7318-36c6-4534-be84-fe78042aeaf2")
+	deliveryID, _ := uuid.Parse("b919ca2c-6b0f-4a22-a61f-8c882ee69323")
+	webhook := postmand.Webhook{ID: webhookID, ContentType: "application/json", MaxBatchSize: 10}
+	createdAt := time.Date(2021, 3, 8, 20, 43, 49, 0, time.UTC)
+	deliveries := []*postmand.Delivery{
+		{ID: deliveryID, WebhookID: webhookID, Payload: "{\n  \"id\": 1\n}", CreatedAt: createdAt},
+		{ID: deliveryID, WebhookID: webhookID, Payload: "order paid", CreatedAt: createdAt},
+		{ID: deliveryID, WebhookID: webhookID, Payload: "/wAB", PayloadEncoding: postmand.DeliveryPayloadEncodingBase64, CreatedAt: createdAt},
+	}
+
+	t.Run("JSON array", func(t *testing.T) {
+		webhook.BatchFormat = postmand.WebhookBatchFormatJSONArray
+		body, headers, err := formatBatchRequest(&webhook, deliveries)
+		assert.Nil(t, err)
+		assert.Equal(t, `[{"id":1},"order paid","/wAB"]`, body)
+		assert.Equal(t, map[string]string{"Content-Type": "application/json"}, headers)
+	})
+
+	t.Run("NDJSON", func(t *testing.T) {
+		webhook.BatchFormat = postmand.WebhookBatchFormatNDJSON
+		body, headers, err := formatBatchRequest(&webhook, deliveries)
+		assert.Nil(t, err)
+		assert.Equal(t, "{\"id\":1}\n\"order paid\"\n\"/wAB\"\n", body)
+		assert.Equal(t, map[string]string{"Content-Type": "application/x-ndjson"}, headers)
+	})
+
+	t.Run("CloudEvents structured", func(t *testing.T) {
+		webhook.BatchFormat = postmand.WebhookBatchFormatJSONArray
+		webhook.DeliveryFormat = postmand.WebhookDeliveryFormatCloudEventsStructured
+		defer func() { webhook.DeliveryFormat = "" }()
+		body, headers, err := formatBatchRequest(&webhook, deliveries[:1])
+		assert.Nil(t, err)
+		assert.JSONEq(
+			t,
+			`[{"specversion":"1.0","id":"b919ca2c-6b0f-4a22-a61f-8c882ee69323","source":"/webhooks/cd9b7318-36c6-4534-be84-fe78042aeaf2","type":"postmand.delivery","time":"2021-03-08T20:43:49Z","datacontenttype":"application/json","data":{"id":1}}]`,
+			body,
+		)
+		assert.Equal(t, map[string]string{"Content-Type": "application/cloudevents-batch+json"}, headers)
+	})
+
+	t.Run("Payload template error", func(t *testing.T) {
+		webhook.PayloadTemplate = `{{ .missing }}`
+		defer func() { webhook.PayloadTemplate = "" }()
+		_, _, err := formatBatchRequest(&webhook, deliveries[:1])
+		assert.Contains(t, err.Error(), "delivery b919ca2c-6b0f-4a22-a61f-8c882ee69323: payload template:")
+	})
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+	"unicode/utf8"
+
+	"github.com/allisson/postmand"
+)
+
+const (
+	cloudEventsSpecVersion = "1.0"
+	// cloudEventsDefaultType is used for deliveries that were not created from an event
+	cloudEventsDefaultType = "postmand.delivery"
+	// cloudEventsStructuredContentType is the content type of the CloudEvents structured JSON mode
+	cloudEventsStructuredContentType = "application/cloudevents+json"
+)
+
+// cloudEvent represents a CloudEvents 1.0 envelope in structured JSON mode.
+type cloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Time            string          `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data,omitempty"`
+	DataBase64      string          `json:"data_base64,omitempty"`
+}
+
+func newCloudEvent(webhook *postmand.Webhook, delivery *postmand.Delivery) cloudEvent {
+	eventType := delivery.EventType
+	if eventType == "" {
+		eventType = cloudEventsDefaultType
+	}
+	return cloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              delivery.ID.String(),
+		Source:          "/webhooks/" + webhook.ID.String(),
+		Type:            eventType,
+		Time:            delivery.CreatedAt.UTC().Format(time.RFC3339Nano),
+		DataContentType: webhook.ContentType,
+	}
+}
+
+// formatRequest returns the body and the headers of the request according to the webhook delivery format.
+func formatRequest(webhook *postmand.Webhook, delivery *postmand.Delivery, payload string) (string, map[string]string, error) {
+	switch webhook.DeliveryFormat {
+	case postmand.WebhookDeliveryFormatCloudEventsStructured:
+		ce := newCloudEvent(webhook, delivery)
+		// A payload that is not a valid JSON is sent as a JSON string and a binary payload as data_base64
+		switch {
+		case json.Valid([]byte(payload)):
+			ce.Data = json.RawMessage(payload)
+		case !utf8.ValidString(payload):
+			ce.DataBase64 = base64.StdEncoding.EncodeToString([]byte(payload))
+		default:
+			data, err := json.Marshal(payload)
+			if err != nil {
+				return "", nil, err
+			}
+			ce.Data = data
+		}
+		body, err := json.Marshal(ce)
+		if err != nil {
+			return "", nil, err
+		}
+		return string(body), map[string]string{"Content-Type": cloudEventsStructuredContentType}, nil
+	case postmand.WebhookDeliveryFormatCloudEventsBinary:
+		ce := newCloudEvent(webhook, delivery)
+		headers := map[string]string{
+			"Content-Type":   webhook.ContentType,
+			"ce-specversion": ce.SpecVersion,
+			"ce-id":          ce.ID,
+			"ce-source":      ce.Source,
+			"ce-type":        ce.Type,
+			"ce-time":        ce.Time,
+		}
+		return payload, headers, nil
+	default:
+		return payload, map[string]string{"Content-Type": webhook.ContentType}, nil
+	}
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/allisson/postmand"
+)
+
+func TestFormatRequest(t *testing.T) {
+	webhookID, _ := uuid.Parse("cd9b7318-36c6-4534-be84-fe78042aeaf2")
+	deliveryID, _ := uuid.Parse("b919ca2c-6b0f-4a22-a61f-8c882ee69323")
+	webhook := postmand.Webhook{ID: webhookID, ContentType: "application/json"}
+	delivery := postmand.Delivery{
+		ID:        deliveryID,
+		WebhookID: webhookID,
+		EventType: "order.created",
+		CreatedAt: time.Date(2021, 3, 8, 20, 43, 49, 0, time.UTC),
+	}
+
+	t.Run("Raw", func(t *testing.T) {
+		webhook.DeliveryFormat = postmand.WebhookDeliveryFormatRaw
+		body, headers, err := formatRequest(&webhook, &delivery, `{"id": 1}`)
+		assert.Nil(t, err)
+		assert.Equal(t, `{"id": 1}`, body)
+		assert.Equal(t, map[string]string{"Content-Type": "application/json"}, headers)
+	})
+
+	t.Run("CloudEvents structured", func(t *testing.T) {
+		webhook.DeliveryFormat = postmand.WebhookDeliveryFormatCloudEventsStructured
+		body, headers, err := formatRequest(&webhook, &delivery, `{"id": 1}`)
+		assert.Nil(t, err)
+		assert.JSONEq(
+			t,
+			`{"specversion":"1.0","id":"b919ca2c-6b0f-4a22-a61f-8c882ee69323","source":"/webhooks/cd9b7318-36c6-4534-be84-fe78042aeaf2","type":"order.created","time":"2021-03-08T20:43:49Z","datacontenttype":"application/json","data":{"id":1}}`,
+			body,
+		)
+		assert.Equal(t, map[string]string{"Content-Type": "application/cloudevents+json"}, headers)
+	})
+
+	t.Run("CloudEvents structured with text payload", func(t *testing.T) {
+		webhook.DeliveryFormat = postmand.WebhookDeliveryFormatCloudEventsStructured
+		deliveryWithoutEvent := delivery
+		deliveryWithoutEvent.EventType = ""
+		body, _, err := formatRequest(&webhook, &deliveryWithoutEvent, `order paid`)
+		assert.Nil(t, err)
+		assert.Contains(t, body, `"type":"postmand.delivery"`)
+		assert.Contains(t, body, `"data":"order paid"`)
+	})
+
+	t.Run("CloudEvents structured with binary payload", func(t *testing.T) {
+		webhook.DeliveryFormat = postmand.WebhookDeliveryFormatCloudEventsStructured
+		body, _, err := formatRequest(&webhook, &delivery, string([]byte{0xff, 0x00, 0x01}))
+		assert.Nil(t, err)
+		assert.Contains(t, body, `"data_base64":"/wAB"`)
+		assert.NotContains(t, body, `"data":`)
+	})
+
+	t.Run("CloudEvents binary", func(t *testing.T) {
+		webhook.DeliveryFormat = postmand.WebhookDeliveryFormatCloudEventsBinary
+		body, headers, err := formatRequest(&webhook, &delivery, `{"id": 1}`)
+		assert.Nil(t, err)
+		assert.Equal(t, `{"id": 1}`, body)
+		assert.Equal(
+			t,
+			map[string]string{
+				"Content-Type":   "application/json",
+				"ce-specversion": "1.0",
+				"ce-id":          "b919ca2c-6b0f-4a22-a61f-8c882ee69323",
+				"ce-source":      "/webhooks/cd9b7318-36c6-4534-be84-fe78042aeaf2",
+				"ce-type":        "order.created",
+				"ce-time":        "2021-03-08T20:43:49Z",
+			},
+			headers,
+		)
+	})
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+
+	"github.com/allisson/postmand"
+)
+
+// compressBody returns the request body compressed according to the webhook compression and the Content-Encoding value,
+// the deflate content coding is the zlib format (RFC 1950) as defined by HTTP.
+func compressBody(compression, body string) (string, string, error) {
+	var buf bytes.Buffer
+	var writer io.WriteCloser
+	switch compression {
+	case postmand.WebhookCompressionGzip:
+		writer = gzip.NewWriter(&buf)
+	case postmand.WebhookCompressionDeflate:
+		writer = zlib.NewWriter(&buf)
+	default:
+		return body, "", nil
+	}
+	if _, err := writer.Write([]byte(body)); err != nil {
+		return "", "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", "", err
+	}
+	return buf.String(), compression, nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/allisson/postmand"
+)
+
+func deadLetterSelectBuilder(filters map[string]interface{}) *sqlbuilder.SelectBuilder {
+	sb := sqlbuilder.PostgreSQL.NewSelectBuilder()
+	columns := append([]string{}, deliveryColumns...)
+	columns = append(
+		columns,
+		"COALESCE(last_attempt.last_attempt_error, '') AS last_attempt_error",
+		"COALESCE(last_attempt.last_attempt_response_status_code, 0) AS last_attempt_response_status_code",
+		"last_attempt.last_attempt_at",
+	)
+	sb.Select(columns...).From("deliveries")
+	// The columns of the last attempt are aliased to avoid ambiguity with the deliveries filters,
+	// replay attempts are ignored because they don't change the delivery
+	sb.JoinWithOption(
+		sqlbuilder.LeftJoin,
+		`LATERAL (
+			SELECT
+				error AS last_attempt_error,
+				response_status_code AS last_attempt_response_status_code,
+				created_at AS last_attempt_at
+			FROM
+				delivery_attempts
+			WHERE
+				delivery_attempts.delivery_id = deliveries.id AND delivery_attempts.kind = 'dispatch'
+			ORDER BY
+				created_at DESC
+			LIMIT
+				1
+		) AS last_attempt`,
+		"true",
+	)
+	sb.Where(sb.Equal("deliveries.status", postmand.DeliveryStatusFailed))
+	sb.Where(filterConditions(&sb.Cond, filters)...)
+	return sb
+}
+
+// DeadLetter implements postmand.DeadLetterRepository interface.
+type DeadLetter struct {
+	db *sqlx.DB
+}
+
+// Get returns a failed postmand.Delivery with its last attempt by options filter.
+func (d DeadLetter) Get(ctx context.Context, getOptions postmand.RepositoryGetOptions) (*postmand.DeadLetter, error) {
+	deadLetter := postmand.DeadLetter{}
+	query, args := deadLetterSelectBuilder(getOptions.Filters).Build()
+	err := d.db.GetContext(ctx, &deadLetter, query, args...)
+	if err == sql.ErrNoRows {
+		return &deadLetter, postmand.ErrDeadLetterNotFound
+	}
+	loadPayload(&deadLetter.Delivery)
+	return &deadLetter, err
+}
+
+// List returns a slice of failed postmand.Delivery with its last attempt by options filter.
+func (d DeadLetter) List(ctx context.Context, listOptions postmand.RepositoryListOptions) ([]*postmand.DeadLetter, error) {
+	deadLetters := []*postmand.DeadLetter{}
+	sb := deadLetterSelectBuilder(listOptions.Filters)
+	sb.Limit(listOptions.Limit).Offset(listOptions.Offset)
+	if listOptions.OrderBy != "" && listOptions.Order != "" {
+		sb.OrderBy("deliveries." + listOptions.OrderBy)
+		switch listOptions.Order {
+		case "asc", "ASC":
+			sb.Asc()
+		case "desc", "DESC":
+			sb.Desc()
+		}
+	}
+	query, args := sb.Build()
+	err := d.db.SelectContext(ctx, &deadLetters, query, args...)
+	for _, deadLetter := range deadLetters {
+		loadPayload(&deadLetter.Delivery)
+	}
+	return deadLetters, err
+}
+
+// Stats returns the amount of failed deliveries grouped by webhook.
+func (d DeadLetter) Stats(ctx context.Context, listOptions postmand.RepositoryListOptions) ([]*postmand.DeadLetterStats, error) {
+	deadLetterStats := []*postmand.DeadLetterStats{}
+	sb := sqlbuilder.PostgreSQL.NewSelectBuilder()
+	sb.Select("webhook_id", "COUNT(*) AS deliveries", "MAX(updated_at) AS last_failed_at").From("deliveries")
+	sb.Where(sb.Equal("status", postmand.DeliveryStatusFailed))
+	sb.Where(filterConditions(&sb.Cond, listOptions.Filters)...)
+	sb.GroupBy("webhook_id").OrderBy("deliveries").Desc()
+	sb.Limit(listOptions.Limit).Offset(listOptions.Offset)
+	query, args := sb.Build()
+	err := d.db.SelectContext(ctx, &deadLetterStats, query, args...)
+	return deadLetterStats, err
+}
+
+// Replay changes the status of a failed postmand.Delivery to pending and schedules it to now.
+func (d DeadLetter) Replay(ctx context.Context, id postmand.ID, resetDeliveryAttempts bool) (*postmand.Delivery, error) {
+	query := fmt.Sprintf(`
+		UPDATE
+			deliveries
+		SET
+			status = $1,
+			scheduled_at = $2,
+			expires_at = NULL,
+			delivery_attempts = CASE WHEN $3 THEN 0 ELSE delivery_attempts END,
+			updated_at = $2
+		WHERE
+			id = $4 AND status = $5
+		RETURNING %s
+	`, deliveryColumnList)
+	delivery := postmand.Delivery{}
+	err := d.db.GetContext(
+		ctx,
+		&delivery,
+		query,
+		postmand.DeliveryStatusPending,
+		time.Now().UTC(),
+		resetDeliveryAttempts,
+		id,
+		postmand.DeliveryStatusFailed,
+	)
+	if err == sql.ErrNoRows {
+		return &delivery, postmand.ErrDeadLetterNotFound
+	}
+	loadPayload(&delivery)
+	return &delivery, err
+}
+
+// Discard changes the status of a failed postmand.Delivery to discarded, removing it from the dead-letter queue.
+func (d DeadLetter) Discard(ctx context.Context, id postmand.ID) (*postmand.Delivery, error) {
+	query := fmt.Sprintf(`
+		UPDATE
+			deliveries
+		SET
+			status = $1, updated_at = $2
+		WHERE
+			id = $3 AND status = $4
+		RETURNING %s
+	`, deliveryColumnList)
+	delivery := postmand.Delivery{}
+	err := d.db.GetContext(ctx, &delivery, query, postmand.DeliveryStatusDiscarded, time.Now().UTC(), id, postmand.DeliveryStatusFailed)
+	if err == sql.ErrNoRows {
+		return &delivery, postmand.ErrDeadLetterNotFound
+	}
+	loadPayload(&delivery)
+	return &delivery, err
+}
+
+// NewDeadLetter will create an implementation of postmand.DeadLetterRepository.
+func NewDeadLetter(db *sqlx.DB) *DeadLetter {
+	return &DeadLetter{db: db}
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/allisson/postmand"
+)
+
+type deadLetterFixture struct {
+	webhook         postmand.Webhook
+	delivery        postmand.Delivery
+	deliveryAttempt postmand.DeliveryAttempt
+}
+
+func makeDeadLetterFixture(ctx context.Context, t *testing.T, th testHelper) deadLetterFixture {
+	webhook := makeWebhook()
+	err := th.webhookRepository.Create(ctx, &webhook)
+	assert.Nil(t, err)
+
+	delivery := makeDelivery()
+	delivery.WebhookID = webhook.ID
+	delivery.Status = postmand.DeliveryStatusFailed
+	delivery.DeliveryAttempts = 1
+	err = th.deliveryRepository.Create(ctx, &delivery)
+	assert.Nil(t, err)
+
+	deliveryAttempt := makeDeliveryAttempt()
+	deliveryAttempt.WebhookID = webhook.ID
+	deliveryAttempt.DeliveryID = delivery.ID
+	deliveryAttempt.ResponseStatusCode = 500
+	deliveryAttempt.Success = false
+	deliveryAttempt.Error = "invalid response status code"
+	deliveryAttempt.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	err = th.deliveryAttemptRepository.Create(ctx, &deliveryAttempt)
+	assert.Nil(t, err)
+
+	return deadLetterFixture{webhook: webhook, delivery: delivery, deliveryAttempt: deliveryAttempt}
+}
+
+func TestDeadLetter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Get dead letter", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		fixture := makeDeadLetterFixture(ctx, t, th)
+
+		options := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": fixture.delivery.ID}}
+		deadLetter, err := th.deadLetterRepository.Get(ctx, options)
+		assert.Nil(t, err)
+		assert.Equal(t, fixture.delivery.ID, deadLetter.ID)
+		assert.Equal(t, "invalid response status code", deadLetter.LastAttemptError)
+		assert.Equal(t, 500, deadLetter.LastAttemptResponseStatusCode)
+		assert.True(t, fixture.deliveryAttempt.CreatedAt.Equal(*deadLetter.LastAttemptAt))
+
+		fixture.delivery.Status = postmand.DeliveryStatusSucceeded
+		err = th.deliveryRepository.Update(ctx, &fixture.delivery)
+		assert.Nil(t, err)
+		_, err = th.deadLetterRepository.Get(ctx, options)
+		assert.Equal(t, postmand.ErrDeadLetterNotFound, err)
+	})
+
+	t.Run("List dead letters", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		fixture := makeDeadLetterFixture(ctx, t, th)
+		pendingDelivery := makeDelivery()
+		pendingDelivery.WebhookID = fixture.webhook.ID
+		err := th.deliveryRepository.Create(ctx, &pendingDelivery)
+		assert.Nil(t, err)
+
+		options := postmand.RepositoryListOptions{
+			Filters: map[string]interface{}{"webhook_id": fixture.webhook.ID},
+			Limit:   10,
+			Offset:  0,
+			OrderBy: "created_at",
+			Order:   "desc",
+		}
+		deadLetters, err := th.deadLetterRepository.List(ctx, options)
+		assert.Nil(t, err)
+		assert.Len(t, deadLetters, 1)
+		assert.Equal(t, fixture.delivery.ID, deadLetters[0].ID)
+		assert.Equal(t, 500, deadLetters[0].LastAttemptResponseStatusCode)
+	})
+
+	t.Run("Stats dead letters", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		fixture := makeDeadLetterFixture(ctx, t, th)
+		delivery := makeDelivery()
+		delivery.WebhookID = fixture.webhook.ID
+		delivery.Status = postmand.DeliveryStatusFailed
+		err := th.deliveryRepository.Create(ctx, &delivery)
+		assert.Nil(t, err)
+
+		options := postmand.RepositoryListOptions{Filters: map[string]interface{}{"webhook_id": fixture.webhook.ID}, Limit: 10, Offset: 0}
+		deadLetterStats, err := th.deadLetterRepository.Stats(ctx, options)
+		assert.Nil(t, err)
+		assert.Len(t, deadLetterStats, 1)
+		assert.Equal(t, fixture.webhook.ID, deadLetterStats[0].WebhookID)
+		assert.Equal(t, 2, deadLetterStats[0].Deliveries)
+	})
+
+	t.Run("Replay dead letter", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		fixture := makeDeadLetterFixture(ctx, t, th)
+
+		delivery, err := th.deadLetterRepository.Replay(ctx, fixture.delivery.ID, true)
+		assert.Nil(t, err)
+		assert.Equal(t, postmand.DeliveryStatusPending, delivery.Status)
+		assert.Equal(t, 0, delivery.DeliveryAttempts)
+
+		_, err = th.deadLetterRepository.Replay(ctx, fixture.delivery.ID, true)
+		assert.Equal(t, postmand.ErrDeadLetterNotFound, err)
+	})
+
+	t.Run("Discard dead letter", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		fixture := makeDeadLetterFixture(ctx, t, th)
+
+		delivery, err := th.deadLetterRepository.Discard(ctx, fixture.delivery.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, postmand.DeliveryStatusDiscarded, delivery.Status)
+
+		_, err = th.deadLetterRepository.Discard(ctx, fixture.delivery.ID)
+		assert.Equal(t, postmand.ErrDeadLetterNotFound, err)
+	})
+}
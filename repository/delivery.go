@@ -6,19 +6,28 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"database/sql"
+	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httputil"
+	"reflect"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/huandu/go-sqlbuilder"
 	"github.com/jmoiron/sqlx"
 	"github.com/jpillora/backoff"
+	"github.com/lib/pq"
 
 	"github.com/allisson/postmand"
 )
 
 type dispatchResponse struct {
+	Compression        string
 	RawRequest         string
 	RawResponse        string
 	ResponseStatusCode int
@@ -27,21 +36,150 @@ type dispatchResponse struct {
 	Error              string
 }
 
-func dispatchToURL(webhook *postmand.Webhook, delivery *postmand.Delivery) dispatchResponse {
+// deliveryColumns are the columns read into postmand.Delivery, payload_json is left out because it duplicates the JSON
+// payloads and is only used by the payload filters.
+var deliveryColumns = func() []string {
+	columns := []string{}
+	for _, column := range sqlbuilder.NewStruct(postmand.Delivery{}).Columns() {
+		if column != "payload_json" {
+			columns = append(columns, "deliveries."+column)
+		}
+	}
+	return columns
+}()
+
+// deliveryColumnList is deliveryColumns separated by commas to be used in the queries.
+var deliveryColumnList = strings.Join(deliveryColumns, ", ")
+
+// deliveryRow returns the delivery as it is stored on database, a base64 payload is stored decoded on payload_bytes
+// and a JSON payload is copied to payload_json for the payload filters.
+func deliveryRow(delivery *postmand.Delivery) (*postmand.Delivery, error) {
+	row := *delivery
+	row.PayloadJSON = nil
+	row.PayloadBytes = nil
+	if row.PayloadEncoding == postmand.DeliveryPayloadEncodingBase64 {
+		body, err := row.Body()
+		if err != nil {
+			return nil, err
+		}
+		row.Payload = ""
+		row.PayloadBytes = body
+		return &row, nil
+	}
+	if json.Valid([]byte(row.Payload)) {
+		payload := row.Payload
+		row.PayloadJSON = &payload
+	}
+	return &row, nil
+}
+
+// loadPayload restores the base64 payload of a delivery read from database.
+func loadPayload(delivery *postmand.Delivery) {
+	if delivery.PayloadEncoding == postmand.DeliveryPayloadEncodingBase64 {
+		delivery.Payload = base64.StdEncoding.EncodeToString(delivery.PayloadBytes)
+	}
+}
+
+// dumpText returns the request or response dump as text, a binary body (invalid UTF-8 or NUL bytes) is base64 encoded
+// because it can't be stored on the delivery attempts.
+func dumpText(dump []byte) string {
+	if utf8.Valid(dump) && bytes.IndexByte(dump, 0) == -1 {
+		return string(dump)
+	}
+	separator := []byte("\r\n\r\n")
+	index := bytes.Index(dump, separator)
+	if index == -1 {
+		return base64.StdEncoding.EncodeToString(dump)
+	}
+	head := dump[:index+len(separator)]
+	body := dump[index+len(separator):]
+	return fmt.Sprintf("%s[binary body, %d bytes, base64 encoded]\r\n%s", head, len(body), base64.StdEncoding.EncodeToString(body))
+}
+
+// renderPayload returns the payload of the delivery transformed by the webhook payload template.
+func renderPayload(webhook *postmand.Webhook, delivery *postmand.Delivery) (string, error) {
+	body, err := delivery.Body()
+	if err != nil {
+		return "", fmt.Errorf("payload: %v", err)
+	}
+	payload := string(body)
+	if webhook.PayloadTemplate != "" {
+		renderedPayload, err := postmand.RenderPayloadTemplate(webhook.PayloadTemplate, payload)
+		if err != nil {
+			return "", fmt.Errorf("payload template: %v", err)
+		}
+		payload = renderedPayload
+	}
+	return payload, nil
+}
+
+// dispatchToURL sends a delivery to url, a webhook in batch mode receives it as a batch with one delivery.
+func dispatchToURL(webhook *postmand.Webhook, delivery *postmand.Delivery, url string) dispatchResponse {
+	if webhook.MaxBatchSize > 1 {
+		return dispatchBatchToURL(webhook, []*postmand.Delivery{delivery}, url)
+	}
+	payload, err := renderPayload(webhook, delivery)
+	if err != nil {
+		return dispatchResponse{Success: false, Error: err.Error()}
+	}
+	requestBody, headers, err := formatRequest(webhook, delivery, payload)
+	if err != nil {
+		return dispatchResponse{Success: false, Error: err.Error()}
+	}
+	return sendRequest(webhook, url, requestBody, headers)
+}
+
+// dispatchBatchToURL sends the deliveries to url in a single request.
+func dispatchBatchToURL(webhook *postmand.Webhook, deliveries []*postmand.Delivery, url string) dispatchResponse {
+	requestBody, headers, err := formatBatchRequest(webhook, deliveries)
+	if err != nil {
+		return dispatchResponse{Success: false, Error: err.Error()}
+	}
+	return sendRequest(webhook, url, requestBody, headers)
+}
+
+// sendRequest sends the request body to url, a webhook with oauth2 credentials refreshes the token and
+// sends the request again once when the receiver answers with 401, the cached token may be revoked before it expires.
+func sendRequest(webhook *postmand.Webhook, url, requestBody string, headers map[string]string) dispatchResponse {
+	dr := sendAuthorizedRequest(webhook, url, requestBody, headers, false)
+	if webhook.OAuth2 != nil && dr.ResponseStatusCode == http.StatusUnauthorized {
+		dr = sendAuthorizedRequest(webhook, url, requestBody, headers, true)
+	}
+	return dr
+}
+
+func sendAuthorizedRequest(webhook *postmand.Webhook, url, requestBody string, headers map[string]string, refreshToken bool) dispatchResponse {
 	dr := dispatchResponse{}
 
+	// The signature is computed over the uncompressed body
+	compressedBody, contentEncoding, err := compressBody(webhook.Compression, requestBody)
+	if err != nil {
+		dr.Success = false
+		dr.Error = fmt.Sprintf("compression: %v", err)
+		return dr
+	}
+	dr.Compression = postmand.WebhookCompressionNone
+	if contentEncoding != "" {
+		dr.Compression = contentEncoding
+	}
+
 	// Prepare request
 	httpClient := &http.Client{Timeout: time.Duration(webhook.DeliveryAttemptTimeout) * time.Second}
-	request, err := http.NewRequest("POST", webhook.URL, bytes.NewBufferString(delivery.Payload))
+	request, err := http.NewRequest("POST", url, bytes.NewBufferString(compressedBody))
 	if err != nil {
 		dr.Success = false
 		dr.Error = err.Error()
 		return dr
 	}
-	request.Header.Set("Content-Type", webhook.ContentType)
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	if contentEncoding != "" {
+		request.Header.Set("Content-Encoding", contentEncoding)
+	}
 	if webhook.SecretToken != "" {
 		hash := hmac.New(sha256.New, []byte(webhook.SecretToken))
-		_, err := hash.Write([]byte(delivery.Payload))
+		_, err := hash.Write([]byte(requestBody))
 		if err != nil {
 			dr.Success = false
 			dr.Error = err.Error()
@@ -58,6 +196,17 @@ func dispatchToURL(webhook *postmand.Webhook, delivery *postmand.Delivery) dispa
 		return dr
 	}
 
+	// The bearer token is set after the dump, it is not stored on the delivery attempts
+	if webhook.OAuth2 != nil {
+		token, err := oauth2Tokens.token(webhook, refreshToken)
+		if err != nil {
+			dr.Success = false
+			dr.Error = fmt.Sprintf("oauth2: %v", err)
+			return dr
+		}
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
 	// Make request
 	start := time.Now()
 	response, err := httpClient.Do(request)
@@ -85,8 +234,8 @@ func dispatchToURL(webhook *postmand.Webhook, delivery *postmand.Delivery) dispa
 	}
 
 	// Update dispatch response
-	dr.RawRequest = string(requestDump)
-	dr.RawResponse = string(responseDump)
+	dr.RawRequest = dumpText(requestDump)
+	dr.RawResponse = dumpText(responseDump)
 	dr.ResponseStatusCode = response.StatusCode
 	dr.ExecutionDuration = int(latency.Milliseconds())
 	dr.Success = success
@@ -102,33 +251,48 @@ type Delivery struct {
 // Get returns postmand.Delivery by options filter.
 func (d Delivery) Get(ctx context.Context, getOptions postmand.RepositoryGetOptions) (*postmand.Delivery, error) {
 	delivery := postmand.Delivery{}
-	query, args := getQuery("deliveries", getOptions)
+	query, args := getQuery("deliveries", getOptions, deliveryColumns...)
 	err := d.db.GetContext(ctx, &delivery, query, args...)
 	if err == sql.ErrNoRows {
 		return &delivery, postmand.ErrDeliveryNotFound
 	}
+	loadPayload(&delivery)
 	return &delivery, err
 }
 
 // List returns a slice of postmand.Delivery by options filter.
 func (d Delivery) List(ctx context.Context, listOptions postmand.RepositoryListOptions) ([]*postmand.Delivery, error) {
 	deliveries := []*postmand.Delivery{}
-	query, args := listQuery("deliveries", listOptions)
+	query, args := listQuery("deliveries", listOptions, deliveryColumns...)
 	err := d.db.SelectContext(ctx, &deliveries, query, args...)
+	for _, delivery := range deliveries {
+		loadPayload(delivery)
+	}
 	return deliveries, err
 }
 
 // Create postmand.Delivery on database.
 func (d Delivery) Create(ctx context.Context, delivery *postmand.Delivery) error {
-	query, args := insertQuery("deliveries", delivery)
-	_, err := d.db.ExecContext(ctx, query, args...)
+	row, err := deliveryRow(delivery)
+	if err != nil {
+		return err
+	}
+	query, args := insertQuery("deliveries", row)
+	_, err = d.db.ExecContext(ctx, query, args...)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" && pqErr.Constraint == "deliveries_idempotency_key_idx" {
+		return postmand.ErrDeliveryIdempotencyKeyConflict
+	}
 	return err
 }
 
 // Update postmand.Delivery on database.
 func (d Delivery) Update(ctx context.Context, delivery *postmand.Delivery) error {
-	query, args := updateQuery("deliveries", delivery.ID, delivery)
-	_, err := d.db.ExecContext(ctx, query, args...)
+	row, err := deliveryRow(delivery)
+	if err != nil {
+		return err
+	}
+	query, args := updateQuery("deliveries", delivery.ID, row)
+	_, err = d.db.ExecContext(ctx, query, args...)
 	return err
 }
 
@@ -141,23 +305,191 @@ func (d Delivery) Delete(ctx context.Context, id postmand.ID) error {
 	return err
 }
 
-// Dispatch fetchs a delivery and send to url destination.
-func (d Delivery) Dispatch(ctx context.Context) (*postmand.DeliveryAttempt, error) {
+// ReleaseIdempotencyKey removes the idempotency key of postmand.Delivery, allowing the key to be used again.
+func (d Delivery) ReleaseIdempotencyKey(ctx context.Context, id postmand.ID) error {
 	query := `
+		UPDATE deliveries SET idempotency_key = '' WHERE id = $1
+	`
+	_, err := d.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// Cancel changes the status of a pending postmand.Delivery to cancelled.
+func (d Delivery) Cancel(ctx context.Context, id postmand.ID) (*postmand.Delivery, error) {
+	// The update waits for the row lock if a worker is dispatching this delivery
+	query := fmt.Sprintf(`
+		UPDATE
+			deliveries
+		SET
+			status = $1, updated_at = $2
+		WHERE
+			id = $3 AND status = $4
+		RETURNING %s
+	`, deliveryColumnList)
+	delivery := postmand.Delivery{}
+	err := d.db.GetContext(ctx, &delivery, query, postmand.DeliveryStatusCancelled, time.Now().UTC(), id, postmand.DeliveryStatusPending)
+	if err == sql.ErrNoRows {
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": id}}
+		if _, err := d.Get(ctx, getOptions); err != nil {
+			return &delivery, err
+		}
+		return &delivery, postmand.ErrDeliveryNotCancellable
+	}
+	loadPayload(&delivery)
+	return &delivery, err
+}
+
+// Retry changes the status of a succeeded or failed postmand.Delivery to pending and schedules it to now.
+func (d Delivery) Retry(ctx context.Context, id postmand.ID, resetDeliveryAttempts bool) (*postmand.Delivery, error) {
+	query := fmt.Sprintf(`
+		UPDATE
+			deliveries
+		SET
+			status = $1,
+			scheduled_at = $2,
+			expires_at = NULL,
+			delivery_attempts = CASE WHEN $3 THEN 0 ELSE delivery_attempts END,
+			updated_at = $2
+		WHERE
+			id = $4 AND status IN ($5, $6)
+		RETURNING %s
+	`, deliveryColumnList)
+	delivery := postmand.Delivery{}
+	err := d.db.GetContext(
+		ctx,
+		&delivery,
+		query,
+		postmand.DeliveryStatusPending,
+		time.Now().UTC(),
+		resetDeliveryAttempts,
+		id,
+		postmand.DeliveryStatusSucceeded,
+		postmand.DeliveryStatusFailed,
+	)
+	if err == sql.ErrNoRows {
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": id}}
+		if _, err := d.Get(ctx, getOptions); err != nil {
+			return &delivery, err
+		}
+		return &delivery, postmand.ErrDeliveryNotRetryable
+	}
+	loadPayload(&delivery)
+	return &delivery, err
+}
+
+// deliveryExpireBatchSize is the amount of deliveries expired by each update.
+const deliveryExpireBatchSize = 100
+
+// Expire moves the pending deliveries that reached the expires_at to the expired status, only the deliveries of the
+// dispatch options queues are expired. Returns the amount of expired deliveries.
+func (d Delivery) Expire(ctx context.Context, dispatchOptions postmand.RepositoryDispatchOptions) (int, error) {
+	queueFilter := ""
+	queryArgs := []interface{}{postmand.DeliveryStatusExpired, time.Now().UTC(), postmand.DeliveryStatusPending, deliveryExpireBatchSize}
+	if len(dispatchOptions.Queues) > 0 {
+		queueFilter = "AND webhook_id IN (SELECT id FROM webhooks WHERE queue = ANY($5))"
+		queryArgs = append(queryArgs, pq.StringArray(dispatchOptions.Queues))
+	}
+	query := fmt.Sprintf(`
+		UPDATE
+			deliveries
+		SET
+			status = $1, updated_at = $2
+		WHERE
+			id IN (
+				SELECT
+					id
+				FROM
+					deliveries
+				WHERE
+					status = $3 AND expires_at <= $2 %s
+				FOR UPDATE SKIP LOCKED
+				LIMIT
+					$4
+			)
+	`, queueFilter)
+
+	// Expire in batches, a large update would lock the deliveries for a long time
+	expired := 0
+	for {
+		result, err := d.db.ExecContext(ctx, query, queryArgs...)
+		if err != nil {
+			return expired, err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return expired, err
+		}
+		expired += int(rowsAffected)
+		if rowsAffected < deliveryExpireBatchSize {
+			return expired, nil
+		}
+	}
+}
+
+// claimBatch locks the other pending deliveries of the webhook that are ready to be dispatched with delivery.
+func claimBatch(ctx context.Context, tx *sqlx.Tx, delivery *postmand.Delivery, limit int) ([]*postmand.Delivery, error) {
+	query := fmt.Sprintf(`
 		SELECT
-			deliveries.*
+			%s
+		FROM
+			deliveries
+		WHERE
+			webhook_id = $1 AND id <> $2 AND status = $3 AND scheduled_at <= $4
+			AND (expires_at IS NULL OR expires_at > $4)
+		ORDER BY
+			priority DESC, created_at ASC
+		FOR UPDATE SKIP LOCKED
+		LIMIT
+			$5
+	`, deliveryColumnList)
+	deliveries := []*postmand.Delivery{}
+	err := tx.SelectContext(ctx, &deliveries, query, delivery.WebhookID, delivery.ID, postmand.DeliveryStatusPending, time.Now().UTC(), limit)
+	for _, batchDelivery := range deliveries {
+		loadPayload(batchDelivery)
+	}
+	return deliveries, err
+}
+
+// Dispatch fetchs a delivery and send to url destination.
+// A webhook in batch mode receives the delivery with the other pending deliveries of the webhook in a single request,
+// the deliveries wait up to max_batch_wait seconds for max_batch_size deliveries.
+func (d Delivery) Dispatch(ctx context.Context, dispatchOptions postmand.RepositoryDispatchOptions) (*postmand.DeliveryAttempt, error) {
+	queueFilter := ""
+	queryArgs := []interface{}{postmand.DeliveryStatusPending, time.Now().UTC(), postmand.WebhookVerificationStatusPending}
+	if len(dispatchOptions.Queues) > 0 {
+		queueFilter = "AND webhooks.queue = ANY($4)"
+		queryArgs = append(queryArgs, pq.StringArray(dispatchOptions.Queues))
+	}
+	query := fmt.Sprintf(`
+		SELECT
+			%s
 		FROM
 			deliveries
 		INNER JOIN webhooks
 			ON deliveries.webhook_id = webhooks.id
 		WHERE
-			webhooks.active = true AND deliveries.status = $1 AND deliveries.scheduled_at <= $2
+			webhooks.active = true AND webhooks.verification_status <> $3
+			AND deliveries.status = $1 AND deliveries.scheduled_at <= $2
+			AND (deliveries.expires_at IS NULL OR deliveries.expires_at > $2) %s
+			AND (
+				webhooks.max_batch_size <= 1
+				OR deliveries.scheduled_at + make_interval(secs => webhooks.max_batch_wait) <= $2
+				OR (
+					SELECT
+						COUNT(*)
+					FROM
+						deliveries AS batch
+					WHERE
+						batch.webhook_id = deliveries.webhook_id AND batch.status = $1 AND batch.scheduled_at <= $2
+						AND (batch.expires_at IS NULL OR batch.expires_at > $2)
+				) >= webhooks.max_batch_size
+			)
 		ORDER BY
-			deliveries.created_at ASC
+			deliveries.priority DESC, deliveries.created_at ASC
 		FOR UPDATE SKIP LOCKED
 		LIMIT
 			1
-	`
+	`, deliveryColumnList, queueFilter)
 
 	// Starts a new transaction
 	tx, err := d.db.Beginx()
@@ -167,7 +499,7 @@ func (d Delivery) Dispatch(ctx context.Context) (*postmand.DeliveryAttempt, erro
 
 	// Get delivery
 	delivery := postmand.Delivery{}
-	err = tx.GetContext(ctx, &delivery, query, postmand.DeliveryStatusPending, time.Now().UTC())
+	err = tx.GetContext(ctx, &delivery, query, queryArgs...)
 	if err != nil {
 		// Skip if no result
 		if err == sql.ErrNoRows {
@@ -177,6 +509,7 @@ func (d Delivery) Dispatch(ctx context.Context) (*postmand.DeliveryAttempt, erro
 		rollback("get delivery", tx)
 		return nil, err
 	}
+	loadPayload(&delivery)
 
 	// Get webhook
 	webhook := postmand.Webhook{}
@@ -188,19 +521,64 @@ func (d Delivery) Dispatch(ctx context.Context) (*postmand.DeliveryAttempt, erro
 		return nil, err
 	}
 
-	// Dispatch webhook
-	dr := dispatchToURL(&webhook, &delivery)
-
-	// Update delivery
-	newDeliveryAttempts := delivery.DeliveryAttempts + 1
-	newStatus := postmand.DeliveryStatusPending
-	newScheduledAt := delivery.ScheduledAt
-	if dr.Success {
-		newStatus = postmand.DeliveryStatusSucceeded
+	// Dispatch webhook, each transaction sends a single request
+	deliveries := []*postmand.Delivery{&delivery}
+	var batchID *postmand.ID
+	var dr dispatchResponse
+	url := webhook.URL
+	urls, err := resolveDestinations(&webhook, &delivery)
+	if err != nil {
+		dr = dispatchResponse{Success: false, Error: err.Error()}
 	} else {
-		if newDeliveryAttempts >= webhook.MaxDeliveryAttempts {
-			newStatus = postmand.DeliveryStatusFailed
+		var position int64
+		if webhook.DestinationStrategy == postmand.WebhookDestinationStrategyRoundRobin && len(urls) > 1 {
+			position, err = nextRoundRobinPosition(ctx, tx, webhook.ID)
+			if err != nil {
+				rollback("next round robin position", tx)
+				return nil, err
+			}
+		}
+		url = urls[selectDestination(&webhook, &delivery, position, len(urls))]
+		if webhook.MaxBatchSize > 1 {
+			batch, err := claimBatch(ctx, tx, &delivery, webhook.MaxBatchSize-1)
+			if err != nil {
+				rollback("claim batch", tx)
+				return nil, err
+			}
+			// The deliveries that resolve to other urls are left pending for the next batch
+			for _, batchDelivery := range batch {
+				if batchURLs, err := resolveDestinations(&webhook, batchDelivery); err == nil && reflect.DeepEqual(batchURLs, urls) {
+					deliveries = append(deliveries, batchDelivery)
+				}
+			}
+			id := uuid.New()
+			batchID = &id
+			dr = dispatchBatchToURL(&webhook, deliveries, url)
 		} else {
+			dr = dispatchToURL(&webhook, &delivery, url)
+		}
+	}
+
+	// With the failover strategy the next dispatch tries the next destination of each delivery,
+	// the delivery attempts counter is incremented only after the last destination fails
+	failover := !dr.Success && urls != nil && usesFailover(&webhook)
+
+	// Update deliveries, each delivery receives a copy of the attempt
+	deliveryAttempts := []*postmand.DeliveryAttempt{}
+	for _, delivery := range deliveries {
+		newDeliveryAttempts := delivery.DeliveryAttempts + 1
+		newStatus := postmand.DeliveryStatusPending
+		newScheduledAt := delivery.ScheduledAt
+		newDestinationIndex := 0
+		switch {
+		case dr.Success:
+			newStatus = postmand.DeliveryStatusSucceeded
+		case failover && delivery.DestinationIndex+1 < len(urls):
+			newDeliveryAttempts = delivery.DeliveryAttempts
+			newDestinationIndex = delivery.DestinationIndex + 1
+		case newDeliveryAttempts >= webhook.MaxDeliveryAttempts:
+			newStatus = postmand.DeliveryStatusFailed
+		default:
 			b := &backoff.Backoff{
 				Min:    time.Duration(webhook.RetryMinBackoff) * time.Second,
 				Max:    time.Duration(webhook.RetryMaxBackoff) * time.Second,
@@ -209,23 +587,95 @@ func (d Delivery) Dispatch(ctx context.Context) (*postmand.DeliveryAttempt, erro
 			}
 			newScheduledAt = time.Now().UTC().Add(b.ForAttempt(float64(delivery.DeliveryAttempts)))
 		}
+		delivery.DestinationIndex = newDestinationIndex
+		delivery.DeliveryAttempts = newDeliveryAttempts
+		delivery.Status = newStatus
+		delivery.ScheduledAt = newScheduledAt
+		delivery.UpdatedAt = time.Now().UTC()
+		row, err := deliveryRow(delivery)
+		if err != nil {
+			rollback("update delivery", tx)
+			return nil, err
+		}
+		query, args = updateQuery("deliveries", delivery.ID, row)
+		_, err = tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			rollback("update delivery", tx)
+			return nil, err
+		}
+
+		// Create delivery attempt
+		deliveryAttempt := postmand.DeliveryAttempt{
+			ID:                 uuid.New(),
+			WebhookID:          webhook.ID,
+			DeliveryID:         delivery.ID,
+			Kind:               postmand.DeliveryAttemptKindDispatch,
+			URL:                url,
+			BatchID:            batchID,
+			Compression:        dr.Compression,
+			RawRequest:         dr.RawRequest,
+			RawResponse:        dr.RawResponse,
+			ResponseStatusCode: dr.ResponseStatusCode,
+			ExecutionDuration:  dr.ExecutionDuration,
+			Success:            dr.Success,
+			Error:              dr.Error,
+			CreatedAt:          time.Now().UTC(),
+		}
+		query, args = insertQuery("delivery_attempts", deliveryAttempt)
+		_, err = tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			rollback("create delivery attempt", tx)
+			return nil, err
+		}
+		deliveryAttempts = append(deliveryAttempts, &deliveryAttempt)
+	}
+
+	if err := tx.Commit(); err != nil {
+		rollback("unable to commit", tx)
+		return nil, err
 	}
-	delivery.DeliveryAttempts = newDeliveryAttempts
-	delivery.Status = newStatus
-	delivery.ScheduledAt = newScheduledAt
-	delivery.UpdatedAt = time.Now().UTC()
-	query, args = updateQuery("deliveries", delivery.ID, delivery)
-	_, err = tx.ExecContext(ctx, query, args...)
+
+	return deliveryAttempts[0], nil
+}
+
+// ReplayToURL sends the payload of a postmand.Delivery to an alternate url using the webhook settings.
+// The delivery is not changed and the result is recorded as a replay delivery attempt,
+// returns postmand.ErrWebhookNotVerified if the webhook is pending verification.
+func (d Delivery) ReplayToURL(ctx context.Context, id postmand.ID, url string) (*postmand.DeliveryAttempt, error) {
+	// Get delivery
+	getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": id}}
+	delivery, err := d.Get(ctx, getOptions)
 	if err != nil {
-		rollback("update delivery", tx)
 		return nil, err
 	}
 
+	// Get webhook
+	webhook := postmand.Webhook{}
+	getOptions = postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": delivery.WebhookID}}
+	query, args := getQuery("webhooks", getOptions)
+	if err := d.db.GetContext(ctx, &webhook, query, args...); err != nil {
+		return nil, err
+	}
+	if webhook.VerificationStatus == postmand.WebhookVerificationStatusPending {
+		return nil, postmand.ErrWebhookNotVerified
+	}
+
+	// The oauth2 token is only sent to the webhook destinations, the alternate url is chosen by the caller
+	if !isDestinationURL(&webhook, url) {
+		webhook.OAuth2 = nil
+	}
+
+	// Dispatch webhook
+	dr := dispatchToURL(&webhook, delivery, url)
+
 	// Create delivery attempt
 	deliveryAttempt := postmand.DeliveryAttempt{
 		ID:                 uuid.New(),
 		WebhookID:          webhook.ID,
 		DeliveryID:         delivery.ID,
+		Kind:               postmand.DeliveryAttemptKindReplay,
+		URL:                url,
+		Compression:        dr.Compression,
 		RawRequest:         dr.RawRequest,
 		RawResponse:        dr.RawResponse,
 		ResponseStatusCode: dr.ResponseStatusCode,
@@ -235,14 +685,7 @@ func (d Delivery) Dispatch(ctx context.Context) (*postmand.DeliveryAttempt, erro
 		CreatedAt:          time.Now().UTC(),
 	}
 	query, args = insertQuery("delivery_attempts", deliveryAttempt)
-	_, err = tx.ExecContext(ctx, query, args...)
-	if err != nil {
-		rollback("create delivery attempt", tx)
-		return nil, err
-	}
-
-	if err := tx.Commit(); err != nil {
-		rollback("unable to commit", tx)
+	if _, err := d.db.ExecContext(ctx, query, args...); err != nil {
 		return nil, err
 	}
 
@@ -14,6 +14,8 @@ import (
 func makeDeliveryAttempt() postmand.DeliveryAttempt {
 	return postmand.DeliveryAttempt{
 		ID:                 uuid.New(),
+		Kind:               postmand.DeliveryAttemptKindDispatch,
+		URL:                "https://httpbin.org/post",
 		ResponseStatusCode: 201,
 		ExecutionDuration:  150,
 		Success:            true,
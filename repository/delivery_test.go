@@ -1,13 +1,21 @@
 package repository
 
 import (
+	"compress/gzip"
+	"compress/zlib"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/allisson/postmand"
@@ -32,7 +40,7 @@ func TestDispatchToURL(t *testing.T) {
 		delivery := makeDelivery()
 		delivery.WebhookID = webhook.ID
 
-		dr := dispatchToURL(&webhook, &delivery)
+		dr := dispatchToURL(&webhook, &delivery, webhook.URL)
 		assert.False(t, dr.Success)
 		assert.Equal(t, `Post "http://localhost:9999": dial tcp [::1]:9999: connect: connection refused`, dr.Error)
 	})
@@ -50,13 +58,79 @@ func TestDispatchToURL(t *testing.T) {
 		delivery := makeDelivery()
 		delivery.WebhookID = webhook.ID
 
-		dr := dispatchToURL(&webhook, &delivery)
+		dr := dispatchToURL(&webhook, &delivery, webhook.URL)
 		assert.NotEqual(t, "", dr.RawResponse)
 		assert.Equal(t, http.StatusNoContent, dr.ResponseStatusCode)
 		assert.False(t, dr.Success)
 		assert.Equal(t, "", dr.Error)
 	})
 
+	t.Run("Binary payload", func(t *testing.T) {
+		var body []byte
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = io.ReadAll(r.Body)
+			// nolint:errcheck
+			w.Write([]byte("OK"))
+		}))
+		defer httpServer.Close()
+
+		webhook := makeWebhook()
+		webhook.URL = httpServer.URL
+		webhook.ContentType = "application/octet-stream"
+		delivery := makeDelivery()
+		delivery.WebhookID = webhook.ID
+		delivery.Payload = "/wABAg=="
+		delivery.PayloadEncoding = postmand.DeliveryPayloadEncodingBase64
+
+		dr := dispatchToURL(&webhook, &delivery, webhook.URL)
+		assert.True(t, dr.Success)
+		assert.Equal(t, []byte{0xff, 0x00, 0x01, 0x02}, body)
+		assert.Contains(t, dr.RawRequest, "Content-Type: application/octet-stream")
+		assert.Contains(t, dr.RawRequest, "[binary body, 4 bytes, base64 encoded]\r\n/wABAg==")
+	})
+
+	t.Run("Compressed payload", func(t *testing.T) {
+		var tests = []struct {
+			compression string
+			decompress  func(io.Reader) (io.Reader, error)
+		}{
+			{postmand.WebhookCompressionGzip, func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+			{postmand.WebhookCompressionDeflate, func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) }},
+		}
+		for _, tt := range tests {
+			var body []byte
+			var contentEncoding, signature string
+			httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contentEncoding = r.Header.Get("Content-Encoding")
+				signature = r.Header.Get("X-Hub-Signature")
+				reader, err := tt.decompress(r.Body)
+				if err == nil {
+					body, _ = io.ReadAll(reader)
+				}
+				// nolint:errcheck
+				w.Write([]byte("OK"))
+			}))
+
+			webhook := makeWebhook()
+			webhook.URL = httpServer.URL
+			webhook.SecretToken = "my-secret-token"
+			webhook.Compression = tt.compression
+			delivery := makeDelivery()
+			delivery.WebhookID = webhook.ID
+
+			dr := dispatchToURL(&webhook, &delivery, webhook.URL)
+			httpServer.Close()
+			assert.True(t, dr.Success)
+			assert.Equal(t, tt.compression, dr.Compression)
+			assert.Equal(t, tt.compression, contentEncoding)
+			assert.Equal(t, delivery.Payload, string(body))
+			hash := hmac.New(sha256.New, []byte(webhook.SecretToken))
+			// nolint:errcheck
+			hash.Write(body)
+			assert.Equal(t, hex.EncodeToString(hash.Sum(nil)), signature)
+		}
+	})
+
 	t.Run("Valid response status code", func(t *testing.T) {
 		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// nolint:errcheck
@@ -69,9 +143,10 @@ func TestDispatchToURL(t *testing.T) {
 		delivery := makeDelivery()
 		delivery.WebhookID = webhook.ID
 
-		dr := dispatchToURL(&webhook, &delivery)
+		dr := dispatchToURL(&webhook, &delivery, webhook.URL)
 		assert.NotEqual(t, "", dr.RawResponse)
 		assert.Equal(t, http.StatusOK, dr.ResponseStatusCode)
+		assert.Equal(t, postmand.WebhookCompressionNone, dr.Compression)
 		assert.True(t, dr.Success)
 		assert.Equal(t, "", dr.Error)
 	})
@@ -94,6 +169,32 @@ func TestDelivery(t *testing.T) {
 		assert.Nil(t, err)
 	})
 
+	t.Run("Create delivery with used idempotency key", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		delivery1 := makeDelivery()
+		delivery1.WebhookID = webhook.ID
+		delivery1.IdempotencyKey = "key"
+		err = th.deliveryRepository.Create(ctx, &delivery1)
+		assert.Nil(t, err)
+
+		delivery2 := makeDelivery()
+		delivery2.WebhookID = webhook.ID
+		delivery2.IdempotencyKey = "key"
+		err = th.deliveryRepository.Create(ctx, &delivery2)
+		assert.Equal(t, postmand.ErrDeliveryIdempotencyKeyConflict, err)
+
+		err = th.deliveryRepository.ReleaseIdempotencyKey(ctx, delivery1.ID)
+		assert.Nil(t, err)
+		err = th.deliveryRepository.Create(ctx, &delivery2)
+		assert.Nil(t, err)
+	})
+
 	t.Run("Update delivery", func(t *testing.T) {
 		th := newTestHelper()
 		defer th.db.Close()
@@ -182,6 +283,128 @@ func TestDelivery(t *testing.T) {
 		assert.Equal(t, delivery2.ID, deliveries[0].ID)
 	})
 
+	t.Run("List deliveries by payload field", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		delivery1 := makeDelivery()
+		delivery1.WebhookID = webhook.ID
+		delivery1.Payload = `{"order_id": 1234, "customer": {"id": "c1"}}`
+		err = th.deliveryRepository.Create(ctx, &delivery1)
+		assert.Nil(t, err)
+
+		delivery2 := makeDelivery()
+		delivery2.WebhookID = webhook.ID
+		delivery2.Payload = `{"order_id": "5678", "customer": {"id": "c2"}}`
+		err = th.deliveryRepository.Create(ctx, &delivery2)
+		assert.Nil(t, err)
+
+		delivery3 := makeDelivery()
+		delivery3.WebhookID = webhook.ID
+		delivery3.Payload = "order_id=1234"
+		err = th.deliveryRepository.Create(ctx, &delivery3)
+		assert.Nil(t, err)
+
+		options := postmand.RepositoryListOptions{Filters: map[string]interface{}{"payload.order_id": "1234"}, Limit: 10, Offset: 0}
+		deliveries, err := th.deliveryRepository.List(ctx, options)
+		assert.Nil(t, err)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, delivery1.ID, deliveries[0].ID)
+
+		options = postmand.RepositoryListOptions{Filters: map[string]interface{}{"payload.order_id": "5678"}, Limit: 10, Offset: 0}
+		deliveries, err = th.deliveryRepository.List(ctx, options)
+		assert.Nil(t, err)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, delivery2.ID, deliveries[0].ID)
+
+		options = postmand.RepositoryListOptions{Filters: map[string]interface{}{"webhook_id": webhook.ID, "payload.customer.id": "c2"}, Limit: 10, Offset: 0}
+		deliveries, err = th.deliveryRepository.List(ctx, options)
+		assert.Nil(t, err)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, delivery2.ID, deliveries[0].ID)
+	})
+
+	t.Run("Create binary delivery", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		delivery := makeDelivery()
+		delivery.WebhookID = webhook.ID
+		delivery.Payload = "/wABAg=="
+		delivery.PayloadEncoding = postmand.DeliveryPayloadEncodingBase64
+		err = th.deliveryRepository.Create(ctx, &delivery)
+		assert.Nil(t, err)
+
+		options := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": delivery.ID}}
+		deliveryFromRepository, err := th.deliveryRepository.Get(ctx, options)
+		assert.Nil(t, err)
+		assert.Equal(t, "/wABAg==", deliveryFromRepository.Payload)
+		assert.Equal(t, []byte{0xff, 0x00, 0x01, 0x02}, deliveryFromRepository.PayloadBytes)
+		assert.Nil(t, deliveryFromRepository.PayloadJSON)
+	})
+
+	t.Run("Cancel delivery", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		delivery := makeDelivery()
+		delivery.WebhookID = webhook.ID
+		err = th.deliveryRepository.Create(ctx, &delivery)
+		assert.Nil(t, err)
+
+		deliveryFromRepository, err := th.deliveryRepository.Cancel(ctx, delivery.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, postmand.DeliveryStatusCancelled, deliveryFromRepository.Status)
+
+		_, err = th.deliveryRepository.Cancel(ctx, delivery.ID)
+		assert.Equal(t, postmand.ErrDeliveryNotCancellable, err)
+
+		_, err = th.deliveryRepository.Cancel(ctx, uuid.New())
+		assert.Equal(t, postmand.ErrDeliveryNotFound, err)
+	})
+
+	t.Run("Retry delivery", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		delivery := makeDelivery()
+		delivery.WebhookID = webhook.ID
+		err = th.deliveryRepository.Create(ctx, &delivery)
+		assert.Nil(t, err)
+
+		_, err = th.deliveryRepository.Retry(ctx, delivery.ID, false)
+		assert.Equal(t, postmand.ErrDeliveryNotRetryable, err)
+
+		delivery.DeliveryAttempts = 1
+		delivery.Status = postmand.DeliveryStatusFailed
+		err = th.deliveryRepository.Update(ctx, &delivery)
+		assert.Nil(t, err)
+
+		deliveryFromRepository, err := th.deliveryRepository.Retry(ctx, delivery.ID, true)
+		assert.Nil(t, err)
+		assert.Equal(t, postmand.DeliveryStatusPending, deliveryFromRepository.Status)
+		assert.Equal(t, 0, deliveryFromRepository.DeliveryAttempts)
+
+		_, err = th.deliveryRepository.Retry(ctx, uuid.New(), false)
+		assert.Equal(t, postmand.ErrDeliveryNotFound, err)
+	})
+
 	t.Run("Dispatch delivery succeeded", func(t *testing.T) {
 		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// nolint:errcheck
@@ -202,7 +425,7 @@ func TestDelivery(t *testing.T) {
 		err = th.deliveryRepository.Create(ctx, &delivery)
 		assert.Nil(t, err)
 
-		_, err = th.deliveryRepository.Dispatch(ctx)
+		_, err = th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{})
 		assert.Nil(t, err)
 
 		options := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": delivery.ID}}
@@ -215,6 +438,502 @@ func TestDelivery(t *testing.T) {
 		deliveryAttemptFromRepository, err := th.deliveryAttemptRepository.Get(ctx, options)
 		assert.Nil(t, err)
 		assert.True(t, deliveryAttemptFromRepository.Success)
+		assert.Equal(t, postmand.DeliveryAttemptKindDispatch, deliveryAttemptFromRepository.Kind)
+		assert.Equal(t, httpServer.URL, deliveryAttemptFromRepository.URL)
+	})
+
+	t.Run("Dispatch delivery batch", func(t *testing.T) {
+		var body []byte
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = io.ReadAll(r.Body)
+			// nolint:errcheck
+			w.Write([]byte("OK"))
+		}))
+		defer httpServer.Close()
+
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		webhook.URL = httpServer.URL
+		webhook.MaxBatchSize = 3
+		webhook.MaxBatchWait = 60
+		webhook.BatchFormat = postmand.WebhookBatchFormatJSONArray
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		// The batch waits for max_batch_size deliveries
+		deliveries := []postmand.Delivery{}
+		for i := 0; i < 3; i++ {
+			delivery := makeDelivery()
+			delivery.WebhookID = webhook.ID
+			delivery.Payload = fmt.Sprintf(`{"id": %d}`, i)
+			delivery.CreatedAt = delivery.CreatedAt.Add(time.Duration(i) * time.Millisecond)
+			err = th.deliveryRepository.Create(ctx, &delivery)
+			assert.Nil(t, err)
+			deliveries = append(deliveries, delivery)
+
+			if i < 2 {
+				deliveryAttempt, err := th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{})
+				assert.Nil(t, err)
+				assert.Nil(t, deliveryAttempt)
+			}
+		}
+
+		deliveryAttempt, err := th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{})
+		assert.Nil(t, err)
+		assert.True(t, deliveryAttempt.Success)
+		assert.NotNil(t, deliveryAttempt.BatchID)
+		assert.Equal(t, `[{"id":0},{"id":1},{"id":2}]`, string(body))
+
+		for _, delivery := range deliveries {
+			options := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": delivery.ID}}
+			deliveryFromRepository, err := th.deliveryRepository.Get(ctx, options)
+			assert.Nil(t, err)
+			assert.Equal(t, postmand.DeliveryStatusSucceeded, deliveryFromRepository.Status)
+
+			options = postmand.RepositoryGetOptions{Filters: map[string]interface{}{"delivery_id": delivery.ID}}
+			deliveryAttemptFromRepository, err := th.deliveryAttemptRepository.Get(ctx, options)
+			assert.Nil(t, err)
+			assert.Equal(t, deliveryAttempt.BatchID, deliveryAttemptFromRepository.BatchID)
+		}
+	})
+
+	t.Run("Dispatch delivery batch after max batch wait", func(t *testing.T) {
+		var body []byte
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = io.ReadAll(r.Body)
+			// nolint:errcheck
+			w.Write([]byte("OK"))
+		}))
+		defer httpServer.Close()
+
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		webhook.URL = httpServer.URL
+		webhook.MaxBatchSize = 10
+		webhook.MaxBatchWait = 60
+		webhook.BatchFormat = postmand.WebhookBatchFormatNDJSON
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		delivery := makeDelivery()
+		delivery.WebhookID = webhook.ID
+		delivery.ScheduledAt = time.Now().UTC().Add(-2 * time.Minute)
+		err = th.deliveryRepository.Create(ctx, &delivery)
+		assert.Nil(t, err)
+
+		deliveryAttempt, err := th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{})
+		assert.Nil(t, err)
+		assert.True(t, deliveryAttempt.Success)
+		assert.Equal(t, "{\"success\":true}\n", string(body))
+	})
+
+	t.Run("Dispatch delivery batch ignores expired deliveries", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		webhook.MaxBatchSize = 2
+		webhook.MaxBatchWait = 60
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		delivery1 := makeDelivery()
+		delivery1.WebhookID = webhook.ID
+		err = th.deliveryRepository.Create(ctx, &delivery1)
+		assert.Nil(t, err)
+
+		expiresAt := time.Now().UTC().Add(-time.Minute)
+		delivery2 := makeDelivery()
+		delivery2.WebhookID = webhook.ID
+		delivery2.ExpiresAt = &expiresAt
+		err = th.deliveryRepository.Create(ctx, &delivery2)
+		assert.Nil(t, err)
+
+		deliveryAttempt, err := th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{})
+		assert.Nil(t, err)
+		assert.Nil(t, deliveryAttempt)
+	})
+
+	t.Run("Dispatch delivery with url placeholders", func(t *testing.T) {
+		var path string
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			// nolint:errcheck
+			w.Write([]byte("OK"))
+		}))
+		defer httpServer.Close()
+
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		webhook.URL = httpServer.URL + "/tenants/{tenant}/events"
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+		delivery := makeDelivery()
+		delivery.WebhookID = webhook.ID
+		delivery.Payload = `{"tenant": "acme"}`
+		err = th.deliveryRepository.Create(ctx, &delivery)
+		assert.Nil(t, err)
+
+		deliveryAttempt, err := th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{})
+		assert.Nil(t, err)
+		assert.True(t, deliveryAttempt.Success)
+		assert.Equal(t, httpServer.URL+"/tenants/acme/events", deliveryAttempt.URL)
+		assert.Equal(t, "/tenants/acme/events", path)
+	})
+
+	t.Run("Dispatch delivery with unresolved url placeholders", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		webhook.URL = "https://httpbin.org/tenants/{tenant}/events"
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+		delivery := makeDelivery()
+		delivery.WebhookID = webhook.ID
+		delivery.Payload = `{"id": 1}`
+		err = th.deliveryRepository.Create(ctx, &delivery)
+		assert.Nil(t, err)
+
+		deliveryAttempt, err := th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{})
+		assert.Nil(t, err)
+		assert.False(t, deliveryAttempt.Success)
+		assert.Equal(t, webhook.URL, deliveryAttempt.URL)
+		assert.Equal(t, "url placeholder {tenant}: payload field not found", deliveryAttempt.Error)
+	})
+
+	t.Run("Dispatch delivery with failover destination", func(t *testing.T) {
+		primaryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer primaryServer.Close()
+		secondaryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// nolint:errcheck
+			w.Write([]byte("OK"))
+		}))
+		defer secondaryServer.Close()
+
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		webhook.URL = primaryServer.URL
+		webhook.Destinations = pq.StringArray{secondaryServer.URL}
+		webhook.DestinationStrategy = postmand.WebhookDestinationStrategyFailover
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+		delivery := makeDelivery()
+		delivery.WebhookID = webhook.ID
+		err = th.deliveryRepository.Create(ctx, &delivery)
+		assert.Nil(t, err)
+
+		deliveryAttempt, err := th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{})
+		assert.Nil(t, err)
+		assert.False(t, deliveryAttempt.Success)
+		assert.Equal(t, primaryServer.URL, deliveryAttempt.URL)
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": delivery.ID}}
+		pendingDelivery, err := th.deliveryRepository.Get(ctx, getOptions)
+		assert.Nil(t, err)
+		assert.Equal(t, postmand.DeliveryStatusPending, pendingDelivery.Status)
+		assert.Equal(t, 0, pendingDelivery.DeliveryAttempts)
+		assert.Equal(t, 1, pendingDelivery.DestinationIndex)
+
+		deliveryAttempt, err = th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{})
+		assert.Nil(t, err)
+		assert.True(t, deliveryAttempt.Success)
+		assert.Equal(t, secondaryServer.URL, deliveryAttempt.URL)
+		succeededDelivery, err := th.deliveryRepository.Get(ctx, getOptions)
+		assert.Nil(t, err)
+		assert.Equal(t, postmand.DeliveryStatusSucceeded, succeededDelivery.Status)
+		assert.Equal(t, 1, succeededDelivery.DeliveryAttempts)
+		assert.Equal(t, 0, succeededDelivery.DestinationIndex)
+	})
+
+	t.Run("Dispatch delivery with round robin destination", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// nolint:errcheck
+			w.Write([]byte("OK"))
+		}))
+		defer server.Close()
+
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		webhook.URL = server.URL + "/a"
+		webhook.Destinations = pq.StringArray{server.URL + "/b"}
+		webhook.DestinationStrategy = postmand.WebhookDestinationStrategyRoundRobin
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		urls := []string{}
+		for i := 0; i < 3; i++ {
+			delivery := makeDelivery()
+			delivery.WebhookID = webhook.ID
+			err = th.deliveryRepository.Create(ctx, &delivery)
+			assert.Nil(t, err)
+			deliveryAttempt, err := th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{})
+			assert.Nil(t, err)
+			urls = append(urls, deliveryAttempt.URL)
+		}
+		assert.Equal(t, []string{server.URL + "/a", server.URL + "/b", server.URL + "/a"}, urls)
+	})
+
+	t.Run("Dispatch delivery batch with failover destination", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		webhook.URL = server.URL + "/primary"
+		webhook.Destinations = pq.StringArray{server.URL + "/secondary"}
+		webhook.DestinationStrategy = postmand.WebhookDestinationStrategyFailover
+		webhook.MaxBatchSize = 2
+		webhook.BatchFormat = postmand.WebhookBatchFormatJSONArray
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		// The second delivery already failed on the primary destination
+		deliveries := []postmand.Delivery{}
+		for i := 0; i < 2; i++ {
+			delivery := makeDelivery()
+			delivery.WebhookID = webhook.ID
+			delivery.DestinationIndex = i
+			delivery.CreatedAt = delivery.CreatedAt.Add(time.Duration(i) * time.Millisecond)
+			err = th.deliveryRepository.Create(ctx, &delivery)
+			assert.Nil(t, err)
+			deliveries = append(deliveries, delivery)
+		}
+
+		deliveryAttempt, err := th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{})
+		assert.Nil(t, err)
+		assert.False(t, deliveryAttempt.Success)
+		assert.Equal(t, server.URL+"/primary", deliveryAttempt.URL)
+
+		options := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": deliveries[0].ID}}
+		firstDelivery, err := th.deliveryRepository.Get(ctx, options)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, firstDelivery.DeliveryAttempts)
+		assert.Equal(t, 1, firstDelivery.DestinationIndex)
+		options = postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": deliveries[1].ID}}
+		secondDelivery, err := th.deliveryRepository.Get(ctx, options)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, secondDelivery.DeliveryAttempts)
+		assert.Equal(t, 0, secondDelivery.DestinationIndex)
+	})
+
+	t.Run("Dispatch delivery pending verification", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		webhook.RequireVerification = true
+		webhook.VerificationStatus = postmand.WebhookVerificationStatusPending
+		webhook.VerificationToken = "my-verification-token"
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		delivery := makeDelivery()
+		delivery.WebhookID = webhook.ID
+		err = th.deliveryRepository.Create(ctx, &delivery)
+		assert.Nil(t, err)
+
+		deliveryAttempt, err := th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{})
+		assert.Nil(t, err)
+		assert.Nil(t, deliveryAttempt)
+	})
+
+	t.Run("Replay delivery to url without oauth2 token", func(t *testing.T) {
+		tokenRequests := 0
+		tokenServer := newTokenServer(t, &tokenRequests)
+		defer tokenServer.Close()
+		authorizations := []string{}
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorizations = append(authorizations, r.Header.Get("Authorization"))
+			// nolint:errcheck
+			w.Write([]byte("OK"))
+		}))
+		defer httpServer.Close()
+
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		webhook.URL = httpServer.URL + "/webhook"
+		webhook.OAuth2 = &postmand.WebhookOAuth2{TokenURL: tokenServer.URL, ClientID: "client", ClientSecret: "secret", Scopes: []string{"events:write"}}
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		delivery := makeDelivery()
+		delivery.WebhookID = webhook.ID
+		delivery.Status = postmand.DeliveryStatusFailed
+		err = th.deliveryRepository.Create(ctx, &delivery)
+		assert.Nil(t, err)
+
+		deliveryAttempt, err := th.deliveryRepository.ReplayToURL(ctx, delivery.ID, httpServer.URL+"/alternate")
+		assert.Nil(t, err)
+		assert.True(t, deliveryAttempt.Success)
+		deliveryAttempt, err = th.deliveryRepository.ReplayToURL(ctx, delivery.ID, webhook.URL)
+		assert.Nil(t, err)
+		assert.True(t, deliveryAttempt.Success)
+		assert.Equal(t, 1, tokenRequests)
+		assert.Equal(t, []string{"", "Bearer token-1"}, authorizations)
+	})
+
+	t.Run("Replay delivery to url", func(t *testing.T) {
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// nolint:errcheck
+			w.Write([]byte("OK"))
+		}))
+		defer httpServer.Close()
+
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		webhook.SecretToken = "my-secret-token"
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		delivery := makeDelivery()
+		delivery.WebhookID = webhook.ID
+		delivery.Status = postmand.DeliveryStatusFailed
+		err = th.deliveryRepository.Create(ctx, &delivery)
+		assert.Nil(t, err)
+
+		deliveryAttempt, err := th.deliveryRepository.ReplayToURL(ctx, delivery.ID, httpServer.URL)
+		assert.Nil(t, err)
+		assert.True(t, deliveryAttempt.Success)
+		assert.Equal(t, postmand.DeliveryAttemptKindReplay, deliveryAttempt.Kind)
+		assert.Equal(t, httpServer.URL, deliveryAttempt.URL)
+		assert.Contains(t, deliveryAttempt.RawRequest, "X-Hub-Signature")
+
+		options := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": delivery.ID}}
+		deliveryFromRepository, err := th.deliveryRepository.Get(ctx, options)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, deliveryFromRepository.DeliveryAttempts)
+		assert.Equal(t, postmand.DeliveryStatusFailed, deliveryFromRepository.Status)
+
+		_, err = th.deliveryRepository.ReplayToURL(ctx, uuid.New(), httpServer.URL)
+		assert.Equal(t, postmand.ErrDeliveryNotFound, err)
+
+		pendingWebhook := makeWebhook()
+		pendingWebhook.RequireVerification = true
+		pendingWebhook.VerificationStatus = postmand.WebhookVerificationStatusPending
+		pendingWebhook.VerificationToken = "my-verification-token"
+		err = th.webhookRepository.Create(ctx, &pendingWebhook)
+		assert.Nil(t, err)
+		pendingDelivery := makeDelivery()
+		pendingDelivery.WebhookID = pendingWebhook.ID
+		err = th.deliveryRepository.Create(ctx, &pendingDelivery)
+		assert.Nil(t, err)
+		_, err = th.deliveryRepository.ReplayToURL(ctx, pendingDelivery.ID, httpServer.URL)
+		assert.Equal(t, postmand.ErrWebhookNotVerified, err)
+	})
+
+	t.Run("Dispatch delivery by priority", func(t *testing.T) {
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// nolint:errcheck
+			w.Write([]byte("OK"))
+		}))
+		defer httpServer.Close()
+
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		webhook.URL = httpServer.URL
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		delivery1 := makeDelivery()
+		delivery1.WebhookID = webhook.ID
+		err = th.deliveryRepository.Create(ctx, &delivery1)
+		assert.Nil(t, err)
+
+		delivery2 := makeDelivery()
+		delivery2.WebhookID = webhook.ID
+		delivery2.Priority = 10
+		err = th.deliveryRepository.Create(ctx, &delivery2)
+		assert.Nil(t, err)
+
+		deliveryAttempt, err := th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, delivery2.ID, deliveryAttempt.DeliveryID)
+	})
+
+	t.Run("Dispatch delivery by queue", func(t *testing.T) {
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// nolint:errcheck
+			w.Write([]byte("OK"))
+		}))
+		defer httpServer.Close()
+
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		webhook.URL = httpServer.URL
+		webhook.Queue = "bulk"
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		delivery := makeDelivery()
+		delivery.WebhookID = webhook.ID
+		err = th.deliveryRepository.Create(ctx, &delivery)
+		assert.Nil(t, err)
+
+		deliveryAttempt, err := th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{Queues: []string{"critical"}})
+		assert.Nil(t, err)
+		assert.Nil(t, deliveryAttempt)
+
+		deliveryAttempt, err = th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{Queues: []string{"critical", "bulk"}})
+		assert.Nil(t, err)
+		assert.Equal(t, delivery.ID, deliveryAttempt.DeliveryID)
+	})
+
+	t.Run("Expire deliveries", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		expiresAt := time.Now().UTC().Add(-time.Minute)
+		delivery := makeDelivery()
+		delivery.WebhookID = webhook.ID
+		delivery.ExpiresAt = &expiresAt
+		err = th.deliveryRepository.Create(ctx, &delivery)
+		assert.Nil(t, err)
+
+		expired, err := th.deliveryRepository.Expire(ctx, postmand.RepositoryDispatchOptions{Queues: []string{"other"}})
+		assert.Nil(t, err)
+		assert.Equal(t, 0, expired)
+
+		expired, err = th.deliveryRepository.Expire(ctx, postmand.RepositoryDispatchOptions{Queues: []string{webhook.Queue}})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, expired)
+
+		deliveryAttempt, err := th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{})
+		assert.Nil(t, err)
+		assert.Nil(t, deliveryAttempt)
+
+		options := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": delivery.ID}}
+		deliveryFromRepository, err := th.deliveryRepository.Get(ctx, options)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, deliveryFromRepository.DeliveryAttempts)
+		assert.Equal(t, postmand.DeliveryStatusExpired, deliveryFromRepository.Status)
 	})
 
 	t.Run("Dispatch delivery retry", func(t *testing.T) {
@@ -239,7 +958,7 @@ func TestDelivery(t *testing.T) {
 		err = th.deliveryRepository.Create(ctx, &delivery)
 		assert.Nil(t, err)
 
-		_, err = th.deliveryRepository.Dispatch(ctx)
+		_, err = th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{})
 		assert.Nil(t, err)
 
 		options := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": delivery.ID}}
@@ -276,7 +995,7 @@ func TestDelivery(t *testing.T) {
 		err = th.deliveryRepository.Create(ctx, &delivery)
 		assert.Nil(t, err)
 
-		_, err = th.deliveryRepository.Dispatch(ctx)
+		_, err = th.deliveryRepository.Dispatch(ctx, postmand.RepositoryDispatchOptions{})
 		assert.Nil(t, err)
 
 		options := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": delivery.ID}}
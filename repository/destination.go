@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"math/rand"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/allisson/postmand"
+)
+
+// resolveDestinations returns the destination urls of the webhook with the placeholders resolved for the delivery.
+func resolveDestinations(webhook *postmand.Webhook, delivery *postmand.Delivery) ([]string, error) {
+	urls := webhook.DestinationURLs()
+	for i, url := range urls {
+		resolvedURL, err := postmand.ResolveWebhookURL(url, delivery)
+		if err != nil {
+			return nil, err
+		}
+		urls[i] = resolvedURL
+	}
+	return urls, nil
+}
+
+// isDestinationURL reports whether url is one of the webhook destinations as they are configured.
+func isDestinationURL(webhook *postmand.Webhook, url string) bool {
+	for _, destinationURL := range webhook.DestinationURLs() {
+		if destinationURL == url {
+			return true
+		}
+	}
+	return false
+}
+
+// usesFailover reports whether the webhook sends a failed attempt to the next destination.
+func usesFailover(webhook *postmand.Webhook) bool {
+	switch webhook.DestinationStrategy {
+	case postmand.WebhookDestinationStrategyRoundRobin, postmand.WebhookDestinationStrategyRandom:
+		return false
+	}
+	return true
+}
+
+// selectDestination returns the index of the destination that receives the delivery attempt: the current destination
+// of the delivery with the failover strategy, the destination at the round robin position with the round_robin strategy
+// and a random destination with the random strategy.
+func selectDestination(webhook *postmand.Webhook, delivery *postmand.Delivery, position int64, destinations int) int {
+	if destinations < 2 {
+		return 0
+	}
+	switch webhook.DestinationStrategy {
+	case postmand.WebhookDestinationStrategyRoundRobin:
+		return int(position % int64(destinations))
+	case postmand.WebhookDestinationStrategyRandom:
+		// nolint:gosec
+		return rand.Intn(destinations)
+	}
+	return delivery.DestinationIndex % destinations
+}
+
+// nextRoundRobinPosition increments the round robin position of the webhook and returns the previous value.
+// The position is shared by the workers and it is updated inside the dispatch transaction.
+func nextRoundRobinPosition(ctx context.Context, tx *sqlx.Tx, webhookID postmand.ID) (int64, error) {
+	query := `
+		INSERT INTO webhook_destination_cursors
+			(webhook_id, next_position)
+		VALUES
+			($1, 1)
+		ON CONFLICT (webhook_id) DO UPDATE SET
+			next_position = webhook_destination_cursors.next_position + 1
+		RETURNING
+			next_position - 1
+	`
+	var position int64
+	err := tx.GetContext(ctx, &position, query, webhookID)
+	return position, err
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/allisson/postmand"
+)
+
+func TestSelectDestination(t *testing.T) {
+	t.Run("Failover", func(t *testing.T) {
+		webhook := postmand.Webhook{ID: uuid.New(), DestinationStrategy: postmand.WebhookDestinationStrategyFailover}
+		assert.Equal(t, 0, selectDestination(&webhook, &postmand.Delivery{}, 5, 3))
+		assert.Equal(t, 2, selectDestination(&webhook, &postmand.Delivery{DestinationIndex: 2}, 5, 3))
+		assert.Equal(t, 0, selectDestination(&webhook, &postmand.Delivery{DestinationIndex: 3}, 5, 3))
+	})
+
+	t.Run("Round robin", func(t *testing.T) {
+		webhook := postmand.Webhook{ID: uuid.New(), DestinationStrategy: postmand.WebhookDestinationStrategyRoundRobin}
+		selected := []int{}
+		for position := int64(0); position < 4; position++ {
+			selected = append(selected, selectDestination(&webhook, &postmand.Delivery{}, position, 3))
+		}
+		assert.Equal(t, []int{0, 1, 2, 0}, selected)
+	})
+
+	t.Run("Random", func(t *testing.T) {
+		webhook := postmand.Webhook{ID: uuid.New(), DestinationStrategy: postmand.WebhookDestinationStrategyRandom}
+		selected := selectDestination(&webhook, &postmand.Delivery{}, 0, 3)
+		assert.GreaterOrEqual(t, selected, 0)
+		assert.Less(t, selected, 3)
+	})
+
+	t.Run("Single destination", func(t *testing.T) {
+		webhook := postmand.Webhook{ID: uuid.New(), DestinationStrategy: postmand.WebhookDestinationStrategyRoundRobin}
+		assert.Equal(t, 0, selectDestination(&webhook, &postmand.Delivery{}, 1, 1))
+	})
+}
+
+func TestIsDestinationURL(t *testing.T) {
+	webhook := postmand.Webhook{URL: "https://a.example.com", Destinations: []string{"https://b.example.com"}}
+	assert.True(t, isDestinationURL(&webhook, "https://a.example.com"))
+	assert.True(t, isDestinationURL(&webhook, "https://b.example.com"))
+	assert.False(t, isDestinationURL(&webhook, "https://attacker.example.com"))
+}
+
+func TestUsesFailover(t *testing.T) {
+	assert.True(t, usesFailover(&postmand.Webhook{}))
+	assert.True(t, usesFailover(&postmand.Webhook{DestinationStrategy: postmand.WebhookDestinationStrategyFailover}))
+	assert.False(t, usesFailover(&postmand.Webhook{DestinationStrategy: postmand.WebhookDestinationStrategyRoundRobin}))
+	assert.False(t, usesFailover(&postmand.Webhook{DestinationStrategy: postmand.WebhookDestinationStrategyRandom}))
+}
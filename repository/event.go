@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/allisson/postmand"
+)
+
+// Event implements postmand.EventRepository interface.
+type Event struct {
+	db *sqlx.DB
+}
+
+// Get returns postmand.Event by options filter.
+func (e Event) Get(ctx context.Context, getOptions postmand.RepositoryGetOptions) (*postmand.Event, error) {
+	event := postmand.Event{}
+	query, args := getQuery("events", getOptions)
+	err := e.db.GetContext(ctx, &event, query, args...)
+	if err == sql.ErrNoRows {
+		return &event, postmand.ErrEventNotFound
+	}
+	return &event, err
+}
+
+// List returns a slice of postmand.Event by options filter.
+func (e Event) List(ctx context.Context, listOptions postmand.RepositoryListOptions) ([]*postmand.Event, error) {
+	events := []*postmand.Event{}
+	query, args := listQuery("events", listOptions)
+	err := e.db.SelectContext(ctx, &events, query, args...)
+	return events, err
+}
+
+// Create postmand.Event and its deliveries on database in the same transaction.
+func (e Event) Create(ctx context.Context, event *postmand.Event, deliveries []*postmand.Delivery) error {
+	// Starts a new transaction
+	tx, err := e.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	// Create event
+	query, args := insertQuery("events", event)
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		rollback("create event", tx)
+		return err
+	}
+
+	// Create deliveries
+	for _, delivery := range deliveries {
+		row, err := deliveryRow(delivery)
+		if err != nil {
+			rollback("create event delivery", tx)
+			return err
+		}
+		query, args := insertQuery("deliveries", row)
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			rollback("create event delivery", tx)
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		rollback("unable to commit", tx)
+		return err
+	}
+
+	return nil
+}
+
+// NewEvent will create an implementation of postmand.EventRepository.
+func NewEvent(db *sqlx.DB) *Event {
+	return &Event{db: db}
+}
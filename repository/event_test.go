@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/allisson/postmand"
+)
+
+func makeEvent() postmand.Event {
+	return postmand.Event{
+		ID:        uuid.New(),
+		EventType: "order.created",
+		Payload:   `{"success": true}`,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func TestEvent(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create event", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		event := makeEvent()
+		event.Deliveries = 1
+		delivery := makeDelivery()
+		delivery.WebhookID = webhook.ID
+		delivery.EventID = &event.ID
+		delivery.EventType = event.EventType
+		err = th.eventRepository.Create(ctx, &event, []*postmand.Delivery{&delivery})
+		assert.Nil(t, err)
+
+		options := postmand.RepositoryListOptions{Filters: map[string]interface{}{"event_id": event.ID}, Limit: 10}
+		deliveries, err := th.deliveryRepository.List(ctx, options)
+		assert.Nil(t, err)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, delivery.ID, deliveries[0].ID)
+		assert.Equal(t, event.EventType, deliveries[0].EventType)
+	})
+
+	t.Run("Create event with invalid delivery", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		event := makeEvent()
+		delivery := makeDelivery()
+		delivery.EventID = &event.ID
+		err := th.eventRepository.Create(ctx, &event, []*postmand.Delivery{&delivery})
+		assert.NotNil(t, err)
+
+		options := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": event.ID}}
+		_, err = th.eventRepository.Get(ctx, options)
+		assert.Equal(t, postmand.ErrEventNotFound, err)
+	})
+
+	t.Run("Get event", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		event := makeEvent()
+		err := th.eventRepository.Create(ctx, &event, nil)
+		assert.Nil(t, err)
+
+		options := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": event.ID}}
+		eventFromRepository, err := th.eventRepository.Get(ctx, options)
+		assert.Nil(t, err)
+		assert.Equal(t, event.ID, eventFromRepository.ID)
+		assert.Equal(t, 0, eventFromRepository.Deliveries)
+
+		options = postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": uuid.New()}}
+		_, err = th.eventRepository.Get(ctx, options)
+		assert.Equal(t, postmand.ErrEventNotFound, err)
+	})
+
+	t.Run("List events", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		event1 := makeEvent()
+		err := th.eventRepository.Create(ctx, &event1, nil)
+		assert.Nil(t, err)
+
+		event2 := makeEvent()
+		event2.EventType = "user.created"
+		err = th.eventRepository.Create(ctx, &event2, nil)
+		assert.Nil(t, err)
+
+		options := postmand.RepositoryListOptions{Filters: map[string]interface{}{"event_type": "user.created"}, Limit: 10}
+		events, err := th.eventRepository.List(ctx, options)
+		assert.Nil(t, err)
+		assert.Len(t, events, 1)
+		assert.Equal(t, event2.ID, events[0].ID)
+	})
+}
@@ -9,6 +9,7 @@ import (
 
 	// For migrate with files
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -41,6 +42,56 @@ func (m *Migration) Run(ctx context.Context) error {
 	return nil
 }
 
+// BackfillPayloadJSON copies the JSON payloads of the deliveries created before the payload_json column to payload_json,
+// the deliveries are updated in batches of batchSize ordered by id so each update locks only a few rows.
+// Returns the amount of updated deliveries.
+func (m *Migration) BackfillPayloadJSON(ctx context.Context, batchSize int) (int, error) {
+	query := `
+		WITH batch AS (
+			SELECT
+				id
+			FROM
+				deliveries
+			WHERE
+				id > $1
+			ORDER BY
+				id
+			LIMIT
+				$2
+		), updated AS (
+			UPDATE
+				deliveries
+			SET
+				payload_json = postmand_try_jsonb(deliveries.payload)
+			FROM
+				batch
+			WHERE
+				deliveries.id = batch.id AND deliveries.payload_json IS NULL AND deliveries.payload_encoding = ''
+			RETURNING
+				deliveries.id
+		)
+		SELECT
+			(SELECT id FROM batch ORDER BY id DESC LIMIT 1) AS last_id,
+			(SELECT COUNT(*) FROM updated) AS updated
+	`
+	updated := 0
+	lastID := uuid.Nil
+	for {
+		result := struct {
+			LastID  uuid.NullUUID `db:"last_id"`
+			Updated int           `db:"updated"`
+		}{}
+		if err := m.db.GetContext(ctx, &result, query, lastID, batchSize); err != nil {
+			return updated, err
+		}
+		if !result.LastID.Valid {
+			return updated, nil
+		}
+		updated += result.Updated
+		lastID = result.LastID.UUID
+	}
+}
+
 // NewMigration will create an implementation of postmand.MigrationRepository.
 func NewMigration(db *sqlx.DB, migrationDir string) *Migration {
 	return &Migration{
@@ -5,14 +5,51 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/allisson/postmand"
 )
 
 func TestMigration(t *testing.T) {
 	ctx := context.Background()
-	th := newTestHelper()
-	defer th.db.Close()
-	migrationDir := "file://../db/migrations"
-	migration := NewMigration(th.db, migrationDir)
-	err := migration.Run(ctx)
-	assert.Nil(t, err)
+
+	t.Run("Run", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+		migrationDir := "file://../db/migrations"
+		migration := NewMigration(th.db, migrationDir)
+		err := migration.Run(ctx)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Backfill payload json", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		payloads := []string{`{"order_id": 1}`, `{"order_id": 2}`, `not json`}
+		for _, payload := range payloads {
+			delivery := makeDelivery()
+			delivery.WebhookID = webhook.ID
+			delivery.Payload = payload
+			err := th.deliveryRepository.Create(ctx, &delivery)
+			assert.Nil(t, err)
+		}
+		_, err = th.db.Exec("UPDATE deliveries SET payload_json = NULL")
+		assert.Nil(t, err)
+
+		migration := NewMigration(th.db, "file://../db/migrations")
+		updated, err := migration.BackfillPayloadJSON(ctx, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, updated)
+
+		options := postmand.RepositoryListOptions{Filters: map[string]interface{}{"payload.order_id": 2}, Limit: 10}
+		deliveries, err := th.deliveryRepository.List(ctx, options)
+		assert.Nil(t, err)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, `{"order_id": 2}`, deliveries[0].Payload)
+		assert.Nil(t, deliveries[0].PayloadJSON)
+	})
 }
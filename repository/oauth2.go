@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/allisson/postmand"
+)
+
+const (
+	// oauth2TokenMaxResponseSize is the max amount of bytes read from the token endpoint response
+	oauth2TokenMaxResponseSize = 65536
+	// oauth2TokenExpiryMargin renews the token before it expires, the request can take a while to reach the receiver
+	oauth2TokenExpiryMargin = 30 * time.Second
+)
+
+type oauth2TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// oauth2TokenKey identifies the credentials of a token, the webhooks that share the credentials share the token.
+type oauth2TokenKey struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       string
+}
+
+type oauth2Token struct {
+	accessToken string
+	expiresAt   time.Time
+}
+
+func (t oauth2Token) valid() bool {
+	return t.expiresAt.IsZero() || time.Now().Add(oauth2TokenExpiryMargin).Before(t.expiresAt)
+}
+
+// oauth2TokenCache keeps the access tokens obtained with the client credentials grant, the tokens are kept in memory by each worker.
+type oauth2TokenCache struct {
+	mu     sync.Mutex
+	tokens map[oauth2TokenKey]oauth2Token
+}
+
+// token returns the cached access token or requests a new one if the token is expired or refresh is true.
+func (c *oauth2TokenCache) token(webhook *postmand.Webhook, refresh bool) (string, error) {
+	key := oauth2TokenKey{
+		tokenURL:     webhook.OAuth2.TokenURL,
+		clientID:     webhook.OAuth2.ClientID,
+		clientSecret: webhook.OAuth2.ClientSecret,
+		scopes:       strings.Join(webhook.OAuth2.Scopes, " "),
+	}
+	c.mu.Lock()
+	token, ok := c.tokens[key]
+	c.mu.Unlock()
+	if ok && !refresh && token.valid() {
+		return token.accessToken, nil
+	}
+
+	timeout := time.Duration(webhook.DeliveryAttemptTimeout) * time.Second
+	token, err := requestOAuth2Token(webhook.OAuth2, timeout)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	c.tokens[key] = token
+	c.mu.Unlock()
+	return token.accessToken, nil
+}
+
+var oauth2Tokens = &oauth2TokenCache{tokens: map[oauth2TokenKey]oauth2Token{}}
+
+// requestOAuth2Token requests an access token using the client credentials grant (RFC 6749, section 4.4),
+// the client credentials are sent with the HTTP Basic authentication scheme.
+func requestOAuth2Token(config *postmand.WebhookOAuth2, timeout time.Duration) (oauth2Token, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(config.Scopes) > 0 {
+		form.Set("scope", strings.Join(config.Scopes, " "))
+	}
+	request, err := http.NewRequest("POST", config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oauth2Token{}, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	request.SetBasicAuth(url.QueryEscape(config.ClientID), url.QueryEscape(config.ClientSecret))
+
+	httpClient := &http.Client{Timeout: timeout}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return oauth2Token{}, err
+	}
+	defer response.Body.Close()
+	responseBody, err := io.ReadAll(io.LimitReader(response.Body, oauth2TokenMaxResponseSize))
+	if err != nil {
+		return oauth2Token{}, err
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return oauth2Token{}, fmt.Errorf("token endpoint returned status code %d: %s", response.StatusCode, strings.TrimSpace(string(responseBody)))
+	}
+
+	tr := oauth2TokenResponse{}
+	if err := json.Unmarshal(responseBody, &tr); err != nil {
+		return oauth2Token{}, fmt.Errorf("invalid token response: %v", err)
+	}
+	if tr.AccessToken == "" {
+		return oauth2Token{}, errors.New("token response without access_token")
+	}
+	if tr.TokenType != "" && !strings.EqualFold(tr.TokenType, "bearer") {
+		return oauth2Token{}, fmt.Errorf("unsupported token type %q", tr.TokenType)
+	}
+	token := oauth2Token{accessToken: tr.AccessToken}
+	if tr.ExpiresIn > 0 {
+		token.expiresAt = time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
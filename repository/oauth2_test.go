@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/allisson/postmand"
+)
+
+func newTokenServer(t *testing.T, tokenRequests *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*tokenRequests++
+		clientID, clientSecret, _ := r.BasicAuth()
+		assert.Equal(t, "client", clientID)
+		assert.Equal(t, "secret", clientSecret)
+		assert.Equal(t, "client_credentials", r.FormValue("grant_type"))
+		assert.Equal(t, "events:write", r.FormValue("scope"))
+		w.Header().Set("Content-Type", "application/json")
+		// nolint:errcheck
+		w.Write([]byte(fmt.Sprintf(`{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, *tokenRequests)))
+	}))
+}
+
+func makeOAuth2Webhook(tokenURL, url string) *postmand.Webhook {
+	return &postmand.Webhook{
+		ID:                     uuid.New(),
+		URL:                    url,
+		ContentType:            "application/json",
+		ValidStatusCodes:       pq.Int32Array{200},
+		DeliveryAttemptTimeout: 1,
+		OAuth2:                 &postmand.WebhookOAuth2{TokenURL: tokenURL, ClientID: "client", ClientSecret: "secret", Scopes: []string{"events:write"}},
+	}
+}
+
+func TestSendRequestWithOAuth2(t *testing.T) {
+	t.Run("Cached token", func(t *testing.T) {
+		tokenRequests := 0
+		tokenServer := newTokenServer(t, &tokenRequests)
+		defer tokenServer.Close()
+		authorizations := []string{}
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorizations = append(authorizations, r.Header.Get("Authorization"))
+			// nolint:errcheck
+			w.Write([]byte("OK"))
+		}))
+		defer httpServer.Close()
+
+		webhook := makeOAuth2Webhook(tokenServer.URL, httpServer.URL)
+		for i := 0; i < 2; i++ {
+			dr := sendRequest(webhook, webhook.URL, `{"success": true}`, map[string]string{"Content-Type": webhook.ContentType})
+			assert.True(t, dr.Success)
+			assert.NotContains(t, dr.RawRequest, "Authorization")
+		}
+		assert.Equal(t, 1, tokenRequests)
+		assert.Equal(t, []string{"Bearer token-1", "Bearer token-1"}, authorizations)
+	})
+
+	t.Run("Unauthorized response refreshes the token", func(t *testing.T) {
+		tokenRequests := 0
+		tokenServer := newTokenServer(t, &tokenRequests)
+		defer tokenServer.Close()
+		authorizations := []string{}
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorizations = append(authorizations, r.Header.Get("Authorization"))
+			if r.Header.Get("Authorization") != "Bearer token-2" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			// nolint:errcheck
+			w.Write([]byte("OK"))
+		}))
+		defer httpServer.Close()
+
+		webhook := makeOAuth2Webhook(tokenServer.URL, httpServer.URL)
+		dr := sendRequest(webhook, webhook.URL, `{"success": true}`, map[string]string{"Content-Type": webhook.ContentType})
+		assert.True(t, dr.Success)
+		assert.Equal(t, 2, tokenRequests)
+		assert.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, authorizations)
+	})
+
+	t.Run("Unauthorized response after the refresh", func(t *testing.T) {
+		tokenRequests := 0
+		tokenServer := newTokenServer(t, &tokenRequests)
+		defer tokenServer.Close()
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer httpServer.Close()
+
+		webhook := makeOAuth2Webhook(tokenServer.URL, httpServer.URL)
+		dr := sendRequest(webhook, webhook.URL, `{"success": true}`, map[string]string{"Content-Type": webhook.ContentType})
+		assert.False(t, dr.Success)
+		assert.Equal(t, http.StatusUnauthorized, dr.ResponseStatusCode)
+		assert.Equal(t, 2, tokenRequests)
+	})
+
+	t.Run("Token endpoint error", func(t *testing.T) {
+		tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			// nolint:errcheck
+			w.Write([]byte(`{"error":"invalid_client"}`))
+		}))
+		defer tokenServer.Close()
+
+		webhook := makeOAuth2Webhook(tokenServer.URL, "https://httpbin.org/post")
+		dr := sendRequest(webhook, webhook.URL, `{"success": true}`, map[string]string{"Content-Type": webhook.ContentType})
+		assert.False(t, dr.Success)
+		assert.Equal(t, `oauth2: token endpoint returned status code 400: {"error":"invalid_client"}`, dr.Error)
+	})
+}
@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
 	"strings"
 
@@ -10,38 +12,84 @@ import (
 	"github.com/allisson/postmand"
 )
 
-func getQuery(tableName string, getOptions postmand.RepositoryGetOptions) (string, []interface{}) {
+func selectColumns(columns []string) []string {
+	if len(columns) == 0 {
+		return []string{"*"}
+	}
+	return columns
+}
+
+// getQuery selects the columns (all columns when columns is empty) of the row that matches the filters.
+func getQuery(tableName string, getOptions postmand.RepositoryGetOptions, columns ...string) (string, []interface{}) {
 	sb := sqlbuilder.PostgreSQL.NewSelectBuilder()
-	sb.Select("*").From(tableName)
+	sb.Select(selectColumns(columns)...).From(tableName)
 	for key, value := range getOptions.Filters {
 		sb.Where(sb.Equal(key, value))
 	}
 	return sb.Build()
 }
 
-func listQuery(tableName string, listOptions postmand.RepositoryListOptions) (string, []interface{}) {
-	sb := sqlbuilder.PostgreSQL.NewSelectBuilder()
-	sb.Select("*").From(tableName).Limit(listOptions.Limit).Offset(listOptions.Offset)
-	for key, value := range listOptions.Filters {
+// payloadFilterPrefix identifies the filters by the fields of the delivery JSON payload (payload.order_id=1234).
+const payloadFilterPrefix = "payload."
+
+// payloadCondition returns a containment condition that uses the payload_json GIN index,
+// the value matches a JSON string or the JSON value it represents (1234 matches "1234" and 1234).
+func payloadCondition(cond *sqlbuilder.Cond, path string, value interface{}) string {
+	rawValue := fmt.Sprint(value)
+	values := []interface{}{rawValue}
+	var jsonValue interface{}
+	if err := json.Unmarshal([]byte(rawValue), &jsonValue); err == nil {
+		if _, ok := jsonValue.(string); !ok {
+			values = append(values, jsonValue)
+		}
+	}
+
+	segments := strings.Split(path, ".")
+	conditions := []string{}
+	for _, v := range values {
+		document := v
+		for i := len(segments) - 1; i >= 0; i-- {
+			document = map[string]interface{}{segments[i]: document}
+		}
+		b, _ := json.Marshal(document)
+		conditions = append(conditions, fmt.Sprintf("payload_json @> %s", cond.Var(string(b))))
+	}
+	return cond.Or(conditions...)
+}
+
+func filterConditions(cond *sqlbuilder.Cond, filters map[string]interface{}) []string {
+	conditions := []string{}
+	for key, value := range filters {
+		if strings.HasPrefix(key, payloadFilterPrefix) {
+			conditions = append(conditions, payloadCondition(cond, strings.TrimPrefix(key, payloadFilterPrefix), value))
+			continue
+		}
 		if strings.Contains(key, ".") {
 			split := strings.Split(key, ".")
 			parsedKey := split[0]
 			compare := split[1]
 			switch compare {
 			case "gt":
-				sb.Where(sb.GreaterThan(parsedKey, value))
+				conditions = append(conditions, cond.GreaterThan(parsedKey, value))
 			case "gte":
-				sb.Where(sb.GreaterEqualThan(parsedKey, value))
+				conditions = append(conditions, cond.GreaterEqualThan(parsedKey, value))
 			case "lt":
-				sb.Where(sb.LessThan(parsedKey, value))
+				conditions = append(conditions, cond.LessThan(parsedKey, value))
 			case "lte":
-				sb.Where(sb.LessEqualThan(parsedKey, value))
+				conditions = append(conditions, cond.LessEqualThan(parsedKey, value))
 			}
 		} else {
-			sb.Where(sb.Equal(key, value))
+			conditions = append(conditions, cond.Equal(key, value))
 		}
-
 	}
+	return conditions
+}
+
+// listQuery selects the columns (all columns when columns is empty) of the rows that match the filters.
+func listQuery(tableName string, listOptions postmand.RepositoryListOptions, columns ...string) (string, []interface{}) {
+	sb := sqlbuilder.PostgreSQL.NewSelectBuilder()
+	sb.Select(selectColumns(columns)...).From(tableName).Limit(listOptions.Limit).Offset(listOptions.Offset)
+	sb.Where(filterConditions(&sb.Cond, listOptions.Filters)...)
 	if listOptions.OrderBy != "" && listOptions.Order != "" {
 		sb.OrderBy(listOptions.OrderBy)
 		switch listOptions.Order {
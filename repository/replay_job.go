@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/huandu/go-sqlbuilder"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/allisson/postmand"
+)
+
+func requeueQuery(replayJob *postmand.ReplayJob) (string, []interface{}) {
+	filters := make(map[string]interface{})
+	for key, value := range replayJob.Filters {
+		filters[key] = value
+	}
+
+	now := time.Now().UTC()
+	ub := sqlbuilder.PostgreSQL.NewUpdateBuilder()
+	ub.Update("deliveries").Set(
+		ub.Assign("status", postmand.DeliveryStatusPending),
+		ub.Assign("scheduled_at", now),
+		"expires_at = NULL",
+		ub.Assign("updated_at", now),
+	)
+	if replayJob.ResetDeliveryAttempts {
+		ub.SetMore(ub.Assign("delivery_attempts", 0))
+	}
+	ub.Where(ub.In("status", postmand.DeliveryStatusSucceeded, postmand.DeliveryStatusFailed))
+	ub.Where(filterConditions(&ub.Cond, filters)...)
+	return ub.Build()
+}
+
+// ReplayJob implements postmand.ReplayJobRepository interface.
+type ReplayJob struct {
+	db *sqlx.DB
+}
+
+// Get returns postmand.ReplayJob by options filter.
+func (r ReplayJob) Get(ctx context.Context, getOptions postmand.RepositoryGetOptions) (*postmand.ReplayJob, error) {
+	replayJob := postmand.ReplayJob{}
+	query, args := getQuery("replay_jobs", getOptions)
+	err := r.db.GetContext(ctx, &replayJob, query, args...)
+	if err == sql.ErrNoRows {
+		return &replayJob, postmand.ErrReplayJobNotFound
+	}
+	return &replayJob, err
+}
+
+// List returns a slice of postmand.ReplayJob by options filter.
+func (r ReplayJob) List(ctx context.Context, listOptions postmand.RepositoryListOptions) ([]*postmand.ReplayJob, error) {
+	replayJobs := []*postmand.ReplayJob{}
+	query, args := listQuery("replay_jobs", listOptions)
+	err := r.db.SelectContext(ctx, &replayJobs, query, args...)
+	return replayJobs, err
+}
+
+// Create postmand.ReplayJob on database.
+func (r ReplayJob) Create(ctx context.Context, replayJob *postmand.ReplayJob) error {
+	query, args := insertQuery("replay_jobs", replayJob)
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// Run executes a pending postmand.ReplayJob, the deliveries are re-queued in the same transaction that completes the job.
+// Returns nil if the replay job is not pending or is being executed by another worker.
+func (r ReplayJob) Run(ctx context.Context, id postmand.ID) (*postmand.ReplayJob, error) {
+	query := `
+		SELECT
+			*
+		FROM
+			replay_jobs
+		WHERE
+			id = $1 AND status = $2
+		FOR UPDATE SKIP LOCKED
+	`
+
+	// Starts a new transaction
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get replay job
+	replayJob := postmand.ReplayJob{}
+	err = tx.GetContext(ctx, &replayJob, query, id, postmand.ReplayJobStatusPending)
+	if err != nil {
+		// Skip if no result
+		if err == sql.ErrNoRows {
+			rollback("replay job not found", tx)
+			return nil, nil
+		}
+		rollback("get replay job", tx)
+		return nil, err
+	}
+
+	// Re-queue deliveries
+	query, args := requeueQuery(&replayJob)
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		rollback("requeue deliveries", tx)
+		replayJob.Status = postmand.ReplayJobStatusFailed
+		replayJob.Error = err.Error()
+		replayJob.UpdatedAt = time.Now().UTC()
+		query, args = updateQuery("replay_jobs", replayJob.ID, replayJob)
+		if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+			return nil, err
+		}
+		return &replayJob, nil
+	}
+	replayedDeliveries, err := result.RowsAffected()
+	if err != nil {
+		rollback("requeue deliveries rows affected", tx)
+		return nil, err
+	}
+
+	// Update replay job
+	replayJob.Status = postmand.ReplayJobStatusCompleted
+	replayJob.ReplayedDeliveries = int(replayedDeliveries)
+	replayJob.UpdatedAt = time.Now().UTC()
+	query, args = updateQuery("replay_jobs", replayJob.ID, replayJob)
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		rollback("update replay job", tx)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		rollback("unable to commit", tx)
+		return nil, err
+	}
+
+	return &replayJob, nil
+}
+
+// NewReplayJob will create an implementation of postmand.ReplayJobRepository.
+func NewReplayJob(db *sqlx.DB) *ReplayJob {
+	return &ReplayJob{db: db}
+}
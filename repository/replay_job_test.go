@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/allisson/postmand"
+)
+
+func makeReplayJob() postmand.ReplayJob {
+	return postmand.ReplayJob{
+		ID:        uuid.New(),
+		Filters:   postmand.ReplayJobFilters{"status": postmand.DeliveryStatusFailed},
+		Status:    postmand.ReplayJobStatusPending,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+}
+
+func TestReplayJob(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Create replay job", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		replayJob := makeReplayJob()
+		err := th.replayJobRepository.Create(ctx, &replayJob)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Get replay job", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		replayJob := makeReplayJob()
+		err := th.replayJobRepository.Create(ctx, &replayJob)
+		assert.Nil(t, err)
+
+		options := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": replayJob.ID}}
+		replayJobFromRepository, err := th.replayJobRepository.Get(ctx, options)
+		assert.Nil(t, err)
+		assert.Equal(t, replayJob.ID, replayJobFromRepository.ID)
+		assert.Equal(t, replayJob.Filters, replayJobFromRepository.Filters)
+
+		options = postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": uuid.New()}}
+		_, err = th.replayJobRepository.Get(ctx, options)
+		assert.Equal(t, postmand.ErrReplayJobNotFound, err)
+	})
+
+	t.Run("List replay jobs", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		replayJob1 := makeReplayJob()
+		err := th.replayJobRepository.Create(ctx, &replayJob1)
+		assert.Nil(t, err)
+		replayJob2 := makeReplayJob()
+		err = th.replayJobRepository.Create(ctx, &replayJob2)
+		assert.Nil(t, err)
+
+		options := postmand.RepositoryListOptions{Limit: 1, Offset: 0, OrderBy: "created_at", Order: "desc"}
+		replayJobs, err := th.replayJobRepository.List(ctx, options)
+		assert.Nil(t, err)
+		assert.Len(t, replayJobs, 1)
+		assert.Equal(t, replayJob2.ID, replayJobs[0].ID)
+	})
+
+	t.Run("Run replay job", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		failedDelivery := makeDelivery()
+		failedDelivery.WebhookID = webhook.ID
+		failedDelivery.Status = postmand.DeliveryStatusFailed
+		failedDelivery.DeliveryAttempts = 1
+		err = th.deliveryRepository.Create(ctx, &failedDelivery)
+		assert.Nil(t, err)
+		succeededDelivery := makeDelivery()
+		succeededDelivery.WebhookID = webhook.ID
+		succeededDelivery.Status = postmand.DeliveryStatusSucceeded
+		err = th.deliveryRepository.Create(ctx, &succeededDelivery)
+		assert.Nil(t, err)
+
+		replayJob := makeReplayJob()
+		replayJob.Filters["webhook_id"] = webhook.ID.String()
+		replayJob.ResetDeliveryAttempts = true
+		err = th.replayJobRepository.Create(ctx, &replayJob)
+		assert.Nil(t, err)
+
+		replayJobFromRepository, err := th.replayJobRepository.Run(ctx, replayJob.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, postmand.ReplayJobStatusCompleted, replayJobFromRepository.Status)
+		assert.Equal(t, 1, replayJobFromRepository.ReplayedDeliveries)
+
+		options := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": failedDelivery.ID}}
+		deliveryFromRepository, err := th.deliveryRepository.Get(ctx, options)
+		assert.Nil(t, err)
+		assert.Equal(t, postmand.DeliveryStatusPending, deliveryFromRepository.Status)
+		assert.Equal(t, 0, deliveryFromRepository.DeliveryAttempts)
+
+		options = postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": succeededDelivery.ID}}
+		deliveryFromRepository, err = th.deliveryRepository.Get(ctx, options)
+		assert.Nil(t, err)
+		assert.Equal(t, postmand.DeliveryStatusSucceeded, deliveryFromRepository.Status)
+
+		// The replay job runs only once.
+		replayJobFromRepository, err = th.replayJobRepository.Run(ctx, replayJob.ID)
+		assert.Nil(t, err)
+		assert.Nil(t, replayJobFromRepository)
+	})
+}
@@ -21,6 +21,9 @@ type testHelper struct {
 	webhookRepository         *Webhook
 	deliveryRepository        *Delivery
 	deliveryAttemptRepository *DeliveryAttempt
+	replayJobRepository       *ReplayJob
+	deadLetterRepository      *DeadLetter
+	eventRepository           *Event
 	pingRepository            *Ping
 }
 
@@ -32,6 +35,9 @@ func newTestHelper() testHelper {
 		webhookRepository:         NewWebhook(db),
 		deliveryRepository:        NewDelivery(db),
 		deliveryAttemptRepository: NewDeliveryAttempt(db),
+		replayJobRepository:       NewReplayJob(db),
+		deadLetterRepository:      NewDeadLetter(db),
+		eventRepository:           NewEvent(db),
 		pingRepository:            NewPing(db),
 	}
 }
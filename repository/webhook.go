@@ -1,14 +1,72 @@
 package repository
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"github.com/allisson/postmand"
 )
 
+// webhookVerificationMaxResponseSize limits the response body read on url verification.
+const webhookVerificationMaxResponseSize = 4096
+
+type webhookVerification struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+}
+
+// verifyURL sends the verification token to url, the endpoint must answer with a 2xx status code
+// and echo the token as the response body or as the challenge field of a json response body.
+func verifyURL(webhook *postmand.Webhook, url string) bool {
+	requestBody, err := json.Marshal(webhookVerification{Type: "url_verification", Challenge: webhook.VerificationToken})
+	if err != nil {
+		return false
+	}
+
+	// Prepare request
+	httpClient := &http.Client{Timeout: time.Duration(webhook.DeliveryAttemptTimeout) * time.Second}
+	request, err := http.NewRequest("POST", url, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return false
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Postmand-Challenge", webhook.VerificationToken)
+
+	// Make request
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return false
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return false
+	}
+	responseBody, err := io.ReadAll(io.LimitReader(response.Body, webhookVerificationMaxResponseSize))
+	if err != nil {
+		return false
+	}
+
+	// Verify echoed token
+	if strings.TrimSpace(string(responseBody)) == webhook.VerificationToken {
+		return true
+	}
+	wv := webhookVerification{}
+	if err := json.Unmarshal(responseBody, &wv); err != nil {
+		return false
+	}
+	return wv.Challenge == webhook.VerificationToken
+}
+
 // Webhook implements postmand.WebhookRepository interface.
 type Webhook struct {
 	db *sqlx.DB
@@ -33,6 +91,23 @@ func (w Webhook) List(ctx context.Context, listOptions postmand.RepositoryListOp
 	return webhooks, err
 }
 
+// ListSubscribed returns the active webhooks subscribed to the event type ordered by creation.
+func (w Webhook) ListSubscribed(ctx context.Context, eventType string) ([]*postmand.Webhook, error) {
+	query := `
+		SELECT
+			*
+		FROM
+			webhooks
+		WHERE
+			active = true AND event_types && $1
+		ORDER BY
+			created_at
+	`
+	webhooks := []*postmand.Webhook{}
+	err := w.db.SelectContext(ctx, &webhooks, query, pq.StringArray(postmand.EventTypePatterns(eventType)))
+	return webhooks, err
+}
+
 // Create postmand.Webhook on database.
 func (w Webhook) Create(ctx context.Context, webhook *postmand.Webhook) error {
 	query, args := insertQuery("webhooks", webhook)
@@ -56,6 +131,91 @@ func (w Webhook) Delete(ctx context.Context, id postmand.ID) error {
 	return err
 }
 
+// Test sends a payload to postmand.Webhook using the same code path of the deliveries.
+// Nothing is stored on database, returns postmand.ErrWebhookNotVerified if the webhook is pending verification.
+func (w Webhook) Test(ctx context.Context, id postmand.ID, payload string) (*postmand.WebhookTestResult, error) {
+	getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": id}}
+	webhook, err := w.Get(ctx, getOptions)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.VerificationStatus == postmand.WebhookVerificationStatusPending {
+		return nil, postmand.ErrWebhookNotVerified
+	}
+
+	// Dispatch webhook
+	delivery := postmand.Delivery{ID: uuid.New(), WebhookID: webhook.ID, Payload: payload, CreatedAt: time.Now().UTC()}
+	var dr dispatchResponse
+	url := webhook.URL
+	urls, err := resolveDestinations(webhook, &delivery)
+	if err != nil {
+		dr = dispatchResponse{Success: false, Error: err.Error()}
+	} else {
+		// The test is always sent to the first destination, the round robin position is kept
+		url = urls[0]
+		dr = dispatchToURL(webhook, &delivery, url)
+	}
+
+	return &postmand.WebhookTestResult{
+		WebhookID:          webhook.ID,
+		URL:                url,
+		Payload:            payload,
+		RawRequest:         dr.RawRequest,
+		RawResponse:        dr.RawResponse,
+		ResponseStatusCode: dr.ResponseStatusCode,
+		ExecutionDuration:  dr.ExecutionDuration,
+		Success:            dr.Success,
+		Error:              dr.Error,
+	}, nil
+}
+
+// Verify sends the verification challenge to a postmand.Webhook that is pending verification.
+// Returns postmand.ErrWebhookVerificationFailed if a destination does not answer the challenge.
+func (w Webhook) Verify(ctx context.Context, id postmand.ID) (*postmand.Webhook, error) {
+	getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": id}}
+	webhook, err := w.Get(ctx, getOptions)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.VerificationStatus != postmand.WebhookVerificationStatusPending {
+		return webhook, nil
+	}
+
+	// Every destination must answer the challenge
+	for _, url := range webhook.DestinationURLs() {
+		if !verifyURL(webhook, url) {
+			return webhook, postmand.ErrWebhookVerificationFailed
+		}
+	}
+
+	// The token filter avoids verifying an url that was changed during the challenge
+	query := `
+		UPDATE
+			webhooks
+		SET
+			verification_status = $1, verified_at = $2, updated_at = $2
+		WHERE
+			id = $3 AND verification_token = $4
+	`
+	now := time.Now().UTC()
+	result, err := w.db.ExecContext(ctx, query, postmand.WebhookVerificationStatusVerified, now, webhook.ID, webhook.VerificationToken)
+	if err != nil {
+		return nil, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return webhook, postmand.ErrWebhookVerificationFailed
+	}
+	webhook.VerificationStatus = postmand.WebhookVerificationStatusVerified
+	webhook.VerifiedAt = &now
+	webhook.UpdatedAt = now
+
+	return webhook, nil
+}
+
 // NewWebhook will create an implementation of postmand.WebhookRepository.
 func NewWebhook(db *sqlx.DB) *Webhook {
 	return &Webhook{db: db}
@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -24,6 +27,11 @@ func makeWebhook() postmand.Webhook {
 		DeliveryAttemptTimeout: 1,
 		RetryMinBackoff:        1,
 		RetryMaxBackoff:        1,
+		Queue:                  postmand.WebhookQueueDefault,
+		VerificationStatus:     postmand.WebhookVerificationStatusNotRequired,
+		EventTypes:             pq.StringArray{},
+		Destinations:           pq.StringArray{},
+		DestinationStrategy:    postmand.WebhookDestinationStrategyFailover,
 		CreatedAt:              time.Now().UTC(),
 		UpdatedAt:              time.Now().UTC(),
 	}
@@ -107,4 +115,186 @@ func TestTransaction(t *testing.T) {
 		assert.Len(t, webhooks, 1)
 		assert.Equal(t, webhook2.ID, webhooks[0].ID)
 	})
+
+	t.Run("List subscribed webhooks", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook1 := makeWebhook()
+		webhook1.EventTypes = pq.StringArray{"order.*"}
+		err := th.webhookRepository.Create(ctx, &webhook1)
+		assert.Nil(t, err)
+
+		webhook2 := makeWebhook()
+		webhook2.EventTypes = pq.StringArray{"order.created"}
+		err = th.webhookRepository.Create(ctx, &webhook2)
+		assert.Nil(t, err)
+
+		webhook3 := makeWebhook()
+		webhook3.EventTypes = pq.StringArray{"user.*"}
+		err = th.webhookRepository.Create(ctx, &webhook3)
+		assert.Nil(t, err)
+
+		webhook4 := makeWebhook()
+		webhook4.EventTypes = pq.StringArray{"*"}
+		webhook4.Active = false
+		err = th.webhookRepository.Create(ctx, &webhook4)
+		assert.Nil(t, err)
+
+		webhooks, err := th.webhookRepository.ListSubscribed(ctx, "order.created")
+		assert.Nil(t, err)
+		assert.Len(t, webhooks, 2)
+		assert.Equal(t, webhook1.ID, webhooks[0].ID)
+		assert.Equal(t, webhook2.ID, webhooks[1].ID)
+	})
+
+	t.Run("Test webhook", func(t *testing.T) {
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// nolint:errcheck
+			w.Write([]byte("OK"))
+		}))
+		defer httpServer.Close()
+
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		webhook.URL = httpServer.URL
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		webhookTestResult, err := th.webhookRepository.Test(ctx, webhook.ID, postmand.WebhookTestPayload)
+		assert.Nil(t, err)
+		assert.True(t, webhookTestResult.Success)
+		assert.Equal(t, http.StatusOK, webhookTestResult.ResponseStatusCode)
+		assert.Contains(t, webhookTestResult.RawRequest, postmand.WebhookTestPayload)
+
+		options := postmand.RepositoryListOptions{Filters: map[string]interface{}{"webhook_id": webhook.ID}, Limit: 10, Offset: 0}
+		deliveries, err := th.deliveryRepository.List(ctx, options)
+		assert.Nil(t, err)
+		assert.Len(t, deliveries, 0)
+
+		_, err = th.webhookRepository.Test(ctx, uuid.New(), postmand.WebhookTestPayload)
+		assert.Equal(t, postmand.ErrWebhookNotFound, err)
+
+		pendingWebhook := makeWebhook()
+		pendingWebhook.URL = httpServer.URL
+		pendingWebhook.RequireVerification = true
+		pendingWebhook.VerificationStatus = postmand.WebhookVerificationStatusPending
+		pendingWebhook.VerificationToken = "my-verification-token"
+		err = th.webhookRepository.Create(ctx, &pendingWebhook)
+		assert.Nil(t, err)
+		_, err = th.webhookRepository.Test(ctx, pendingWebhook.ID, postmand.WebhookTestPayload)
+		assert.Equal(t, postmand.ErrWebhookNotVerified, err)
+	})
+
+	t.Run("Test webhook with payload template", func(t *testing.T) {
+		var body []byte
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = io.ReadAll(r.Body)
+			// nolint:errcheck
+			w.Write([]byte("OK"))
+		}))
+		defer httpServer.Close()
+
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		webhook.URL = httpServer.URL
+		webhook.PayloadTemplate = `{"text": {{ json .event }}}`
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		webhookTestResult, err := th.webhookRepository.Test(ctx, webhook.ID, postmand.WebhookTestPayload)
+		assert.Nil(t, err)
+		assert.True(t, webhookTestResult.Success)
+		assert.Equal(t, `{"text": "postmand.test"}`, string(body))
+
+		webhook.PayloadTemplate = `{"text": {{ .missing }}}`
+		err = th.webhookRepository.Update(ctx, &webhook)
+		assert.Nil(t, err)
+
+		webhookTestResult, err = th.webhookRepository.Test(ctx, webhook.ID, postmand.WebhookTestPayload)
+		assert.Nil(t, err)
+		assert.False(t, webhookTestResult.Success)
+		assert.Contains(t, webhookTestResult.Error, "payload template:")
+	})
+
+	t.Run("Verify webhook", func(t *testing.T) {
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// nolint:errcheck
+			w.Write([]byte(r.Header.Get("X-Postmand-Challenge")))
+		}))
+		defer httpServer.Close()
+
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		webhook.URL = httpServer.URL
+		webhook.RequireVerification = true
+		webhook.VerificationStatus = postmand.WebhookVerificationStatusPending
+		webhook.VerificationToken = "my-verification-token"
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		webhookFromRepository, err := th.webhookRepository.Verify(ctx, webhook.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, postmand.WebhookVerificationStatusVerified, webhookFromRepository.VerificationStatus)
+		assert.NotNil(t, webhookFromRepository.VerifiedAt)
+
+		options := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookFromRepository, err = th.webhookRepository.Get(ctx, options)
+		assert.Nil(t, err)
+		assert.Equal(t, postmand.WebhookVerificationStatusVerified, webhookFromRepository.VerificationStatus)
+	})
+
+	t.Run("Verify webhook failed", func(t *testing.T) {
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// nolint:errcheck
+			w.Write([]byte("OK"))
+		}))
+		defer httpServer.Close()
+
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		webhook.URL = httpServer.URL
+		webhook.RequireVerification = true
+		webhook.VerificationStatus = postmand.WebhookVerificationStatusPending
+		webhook.VerificationToken = "my-verification-token"
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		webhookFromRepository, err := th.webhookRepository.Verify(ctx, webhook.ID)
+		assert.Equal(t, postmand.ErrWebhookVerificationFailed, err)
+		assert.Equal(t, postmand.WebhookVerificationStatusPending, webhookFromRepository.VerificationStatus)
+	})
+
+	t.Run("Verify webhook with token changed during the challenge", func(t *testing.T) {
+		th := newTestHelper()
+		defer th.db.Close()
+
+		webhook := makeWebhook()
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, err := th.db.Exec("UPDATE webhooks SET verification_token = $1 WHERE id = $2", "new-verification-token", webhook.ID)
+			assert.Nil(t, err)
+			// nolint:errcheck
+			w.Write([]byte(r.Header.Get("X-Postmand-Challenge")))
+		}))
+		defer httpServer.Close()
+
+		webhook.URL = httpServer.URL
+		webhook.RequireVerification = true
+		webhook.VerificationStatus = postmand.WebhookVerificationStatusPending
+		webhook.VerificationToken = "my-verification-token"
+		err := th.webhookRepository.Create(ctx, &webhook)
+		assert.Nil(t, err)
+
+		webhookFromRepository, err := th.webhookRepository.Verify(ctx, webhook.ID)
+		assert.Equal(t, postmand.ErrWebhookVerificationFailed, err)
+		assert.Equal(t, postmand.WebhookVerificationStatusPending, webhookFromRepository.VerificationStatus)
+	})
 }
@@ -0,0 +1,423 @@
+package postmand
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// payloadSchemaTypes are the JSON Schema types supported by the type keyword.
+var payloadSchemaTypes = []string{"null", "boolean", "object", "array", "number", "integer", "string"}
+
+// payloadSchemaTypeList accepts the type keyword as a string or as an array of strings.
+type payloadSchemaTypeList []string
+
+func (t *payloadSchemaTypeList) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*t = payloadSchemaTypeList{value}
+		return nil
+	}
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return errors.New("type must be a string or an array of strings")
+	}
+	*t = values
+	return nil
+}
+
+// payloadSchemaAdditional accepts the additionalProperties keyword as a boolean or as a schema.
+type payloadSchemaAdditional struct {
+	allowed bool
+	schema  *payloadSchemaNode
+}
+
+func (a *payloadSchemaAdditional) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.allowed); err == nil {
+		return nil
+	}
+	a.allowed = true
+	a.schema = &payloadSchemaNode{}
+	return json.Unmarshal(data, a.schema)
+}
+
+// payloadSchemaAnnotations are the JSON Schema keywords accepted without changing the validation.
+var payloadSchemaAnnotations = []string{"$schema", "$id", "$comment", "title", "description", "default", "examples"}
+
+// payloadSchemaNode contains the supported subset of the JSON Schema keywords, a schema with other keywords is rejected
+// because ignoring them (eg: $ref or oneOf) would accept payloads that the schema author expects to be rejected.
+type payloadSchemaNode struct {
+	Type                 payloadSchemaTypeList         `json:"type"`
+	Properties           map[string]*payloadSchemaNode `json:"properties"`
+	Required             []string                      `json:"required"`
+	AdditionalProperties *payloadSchemaAdditional      `json:"additionalProperties"`
+	Items                *payloadSchemaNode            `json:"items"`
+	Enum                 []json.RawMessage             `json:"enum"`
+	Const                json.RawMessage               `json:"const"`
+	Minimum              *float64                      `json:"minimum"`
+	Maximum              *float64                      `json:"maximum"`
+	ExclusiveMinimum     *float64                      `json:"exclusiveMinimum"`
+	ExclusiveMaximum     *float64                      `json:"exclusiveMaximum"`
+	MinLength            *int                          `json:"minLength"`
+	MaxLength            *int                          `json:"maxLength"`
+	Pattern              string                        `json:"pattern"`
+	MinItems             *int                          `json:"minItems"`
+	MaxItems             *int                          `json:"maxItems"`
+
+	enum                []interface{}
+	constValue          interface{}
+	pattern             *regexp.Regexp
+	unsupportedKeywords []string
+}
+
+// payloadSchemaKeywords are the annotations and the keywords of the payloadSchemaNode fields.
+var payloadSchemaKeywords = func() map[string]bool {
+	keywords := map[string]bool{}
+	for _, keyword := range payloadSchemaAnnotations {
+		keywords[keyword] = true
+	}
+	nodeType := reflect.TypeOf(payloadSchemaNode{})
+	for i := 0; i < nodeType.NumField(); i++ {
+		if tag := nodeType.Field(i).Tag.Get("json"); tag != "" {
+			keywords[tag] = true
+		}
+	}
+	return keywords
+}()
+
+func (n *payloadSchemaNode) UnmarshalJSON(data []byte) error {
+	// The alias type has the same fields without this method
+	type payloadSchemaNodeAlias payloadSchemaNode
+	if err := json.Unmarshal(data, (*payloadSchemaNodeAlias)(n)); err != nil {
+		return err
+	}
+	keywords := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &keywords); err != nil {
+		return err
+	}
+	n.unsupportedKeywords = nil
+	for keyword := range keywords {
+		if !payloadSchemaKeywords[keyword] {
+			n.unsupportedKeywords = append(n.unsupportedKeywords, keyword)
+		}
+	}
+	sort.Strings(n.unsupportedKeywords)
+	return nil
+}
+
+// compile validates the keywords and prepares the enum values and the pattern regex.
+func (n *payloadSchemaNode) compile(path string) error {
+	if len(n.unsupportedKeywords) > 0 {
+		return fmt.Errorf("%s: unsupported keyword %q", path, n.unsupportedKeywords[0])
+	}
+	for _, t := range n.Type {
+		found := false
+		for _, supportedType := range payloadSchemaTypes {
+			if t == supportedType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: unsupported type %q", path, t)
+		}
+	}
+	for _, rawValue := range n.Enum {
+		value, err := decodeSchemaValue(rawValue)
+		if err != nil {
+			return fmt.Errorf("%s: invalid enum value", path)
+		}
+		n.enum = append(n.enum, value)
+	}
+	if n.Const != nil {
+		constValue, err := decodeSchemaValue(n.Const)
+		if err != nil {
+			return fmt.Errorf("%s: invalid const value", path)
+		}
+		n.constValue = constValue
+	}
+	if n.Pattern != "" {
+		pattern, err := regexp.Compile(n.Pattern)
+		if err != nil {
+			return fmt.Errorf("%s: invalid pattern: %w", path, err)
+		}
+		n.pattern = pattern
+	}
+	for name, property := range n.Properties {
+		if property == nil {
+			return fmt.Errorf("%s: invalid schema for property %q", path, name)
+		}
+		if err := property.compile(path + ".properties." + name); err != nil {
+			return err
+		}
+	}
+	if n.AdditionalProperties != nil && n.AdditionalProperties.schema != nil {
+		if err := n.AdditionalProperties.schema.compile(path + ".additionalProperties"); err != nil {
+			return err
+		}
+	}
+	if n.Items != nil {
+		if err := n.Items.compile(path + ".items"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n *payloadSchemaNode) validate(value interface{}, path string, errs validation.Errors) {
+	if len(n.Type) > 0 && !n.matchType(value) {
+		errs[path] = fmt.Errorf("must be of type %s", strings.Join(n.Type, " or "))
+		return
+	}
+	if n.enum != nil && !containsSchemaValue(n.enum, value) {
+		errs[path] = errors.New("must be one of the allowed values")
+		return
+	}
+	if n.Const != nil && !reflect.DeepEqual(n.constValue, value) {
+		errs[path] = fmt.Errorf("must be equal to %s", string(n.Const))
+		return
+	}
+
+	switch v := value.(type) {
+	case json.Number:
+		number, _ := v.Float64()
+		n.validateNumber(number, path, errs)
+	case string:
+		n.validateString(v, path, errs)
+	case []interface{}:
+		n.validateArray(v, path, errs)
+	case map[string]interface{}:
+		n.validateObject(v, path, errs)
+	}
+}
+
+func (n *payloadSchemaNode) matchType(value interface{}) bool {
+	for _, t := range n.Type {
+		switch value.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case json.Number:
+			if t == "number" {
+				return true
+			}
+			if t == "integer" {
+				number, err := value.(json.Number).Float64()
+				if err == nil && number == math.Trunc(number) {
+					return true
+				}
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case []interface{}:
+			if t == "array" {
+				return true
+			}
+		case map[string]interface{}:
+			if t == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (n *payloadSchemaNode) validateNumber(value float64, path string, errs validation.Errors) {
+	switch {
+	case n.Minimum != nil && value < *n.Minimum:
+		errs[path] = fmt.Errorf("must be no less than %v", *n.Minimum)
+	case n.ExclusiveMinimum != nil && value <= *n.ExclusiveMinimum:
+		errs[path] = fmt.Errorf("must be greater than %v", *n.ExclusiveMinimum)
+	case n.Maximum != nil && value > *n.Maximum:
+		errs[path] = fmt.Errorf("must be no greater than %v", *n.Maximum)
+	case n.ExclusiveMaximum != nil && value >= *n.ExclusiveMaximum:
+		errs[path] = fmt.Errorf("must be less than %v", *n.ExclusiveMaximum)
+	}
+}
+
+func (n *payloadSchemaNode) validateString(value, path string, errs validation.Errors) {
+	length := utf8.RuneCountInString(value)
+	switch {
+	case n.MinLength != nil && length < *n.MinLength:
+		errs[path] = fmt.Errorf("the length must be no less than %d", *n.MinLength)
+	case n.MaxLength != nil && length > *n.MaxLength:
+		errs[path] = fmt.Errorf("the length must be no more than %d", *n.MaxLength)
+	case n.pattern != nil && !n.pattern.MatchString(value):
+		errs[path] = errors.New("must be in a valid format")
+	}
+}
+
+func (n *payloadSchemaNode) validateArray(value []interface{}, path string, errs validation.Errors) {
+	switch {
+	case n.MinItems != nil && len(value) < *n.MinItems:
+		errs[path] = fmt.Errorf("must contain at least %d items", *n.MinItems)
+		return
+	case n.MaxItems != nil && len(value) > *n.MaxItems:
+		errs[path] = fmt.Errorf("must contain at most %d items", *n.MaxItems)
+		return
+	}
+	if n.Items == nil {
+		return
+	}
+	for i, item := range value {
+		n.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), errs)
+	}
+}
+
+func (n *payloadSchemaNode) validateObject(value map[string]interface{}, path string, errs validation.Errors) {
+	for _, name := range n.Required {
+		if _, ok := value[name]; !ok {
+			errs[schemaFieldPath(path, name)] = errors.New("is required")
+		}
+	}
+
+	// Sorted keys keep the validation of nested fields deterministic
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if property, ok := n.Properties[name]; ok {
+			property.validate(value[name], schemaFieldPath(path, name), errs)
+			continue
+		}
+		if n.AdditionalProperties == nil {
+			continue
+		}
+		if !n.AdditionalProperties.allowed {
+			errs[schemaFieldPath(path, name)] = errors.New("is not allowed")
+			continue
+		}
+		if n.AdditionalProperties.schema != nil {
+			n.AdditionalProperties.schema.validate(value[name], schemaFieldPath(path, name), errs)
+		}
+	}
+}
+
+// schemaFieldPath uses the dot notation for field names and the bracket notation for the other ones, eg: payload["sku-id"].
+func schemaFieldPath(path, name string) string {
+	if payloadSchemaFieldRegex.MatchString(name) {
+		return path + "." + name
+	}
+	return path + "[" + strconv.Quote(name) + "]"
+}
+
+var payloadSchemaFieldRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// decodeSchemaValue decodes a JSON value using json.Number, the same representation used for the payload.
+func decodeSchemaValue(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+	return normalizeSchemaValue(value), nil
+}
+
+// normalizeSchemaValue rewrites the numbers in canonical form, so 1, 1.0 and 1e0 are equal.
+func normalizeSchemaValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		number, err := v.Float64()
+		if err != nil {
+			return v
+		}
+		return json.Number(strconv.FormatFloat(number, 'g', -1, 64))
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeSchemaValue(v[i])
+		}
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = normalizeSchemaValue(v[key])
+		}
+	}
+	return value
+}
+
+func containsSchemaValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// PayloadSchema is a parsed JSON Schema used to validate the payloads sent to a webhook.
+// The supported keywords are type, properties, required, additionalProperties, items, enum, const,
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum, minLength, maxLength, pattern, minItems and maxItems,
+// the annotations ($schema, $id, $comment, title, description, default and examples) are accepted and ignored.
+type PayloadSchema struct {
+	root *payloadSchemaNode
+}
+
+// Validate returns a *PayloadValidationError if the payload is not a valid JSON or does not conform to the schema.
+func (s *PayloadSchema) Validate(payload string) error {
+	value, err := decodeSchemaValue([]byte(payload))
+	if err != nil {
+		return &PayloadValidationError{Errors: validation.Errors{payloadFieldName: errors.New("must be a valid JSON")}}
+	}
+	errs := validation.Errors{}
+	s.root.validate(value, payloadFieldName, errs)
+	if len(errs) > 0 {
+		return &PayloadValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// ParsePayloadSchema returns a PayloadSchema or an error describing the invalid part of the schema.
+func ParsePayloadSchema(schema string) (*PayloadSchema, error) {
+	root := &payloadSchemaNode{}
+	if err := json.Unmarshal([]byte(schema), root); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	if err := root.compile("schema"); err != nil {
+		return nil, err
+	}
+	return &PayloadSchema{root: root}, nil
+}
+
+// ValidatePayloadSchema validates the payload against the JSON Schema, an empty schema accepts any payload.
+func ValidatePayloadSchema(schema, payload string) error {
+	if schema == "" {
+		return nil
+	}
+	payloadSchema, err := ParsePayloadSchema(schema)
+	if err != nil {
+		return err
+	}
+	return payloadSchema.Validate(payload)
+}
+
+func validatePayloadSchema(value interface{}) error {
+	schema, _ := value.(string)
+	if schema == "" {
+		return nil
+	}
+	_, err := ParsePayloadSchema(schema)
+	return err
+}
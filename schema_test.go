@@ -0,0 +1,94 @@
+package postmand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePayloadSchema(t *testing.T) {
+	schema := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "Order",
+		"type": "object",
+		"required": ["order_id", "customer"],
+		"additionalProperties": false,
+		"properties": {
+			"order_id": {"type": "integer", "minimum": 1},
+			"status": {"enum": ["paid", "refunded"]},
+			"version": {"const": 1},
+			"customer": {
+				"type": "object",
+				"required": ["id"],
+				"properties": {
+					"id": {"type": "string", "minLength": 2},
+					"email": {"type": ["string", "null"], "pattern": "^[^@]+@[^@]+$"}
+				}
+			},
+			"items": {
+				"type": "array",
+				"minItems": 1,
+				"items": {"type": "object", "properties": {"sku-id": {"type": "string", "maxLength": 3}}}
+			},
+			"amount": {"type": "number", "exclusiveMinimum": 0, "maximum": 1000}
+		}
+	}`
+
+	var tests = []struct {
+		kind          string
+		payload       string
+		expectedError string
+	}{
+		{"Valid payload", `{"order_id": 1234, "status": "paid", "version": 1.0, "customer": {"id": "c1", "email": null}, "items": [{"sku-id": "abc"}], "amount": 10.5}`, ""},
+		{"Not JSON payload", `order_id=1234`, "payload: must be a valid JSON."},
+		{"Trailing data", `{"order_id": 1, "customer": {"id": "c1"}} {}`, "payload: must be a valid JSON."},
+		{"Wrong root type", `[]`, "payload: must be of type object."},
+		{"Required fields", `{"customer": {}}`, "payload.customer.id: is required; payload.order_id: is required."},
+		{"Integer", `{"order_id": 1.5, "customer": {"id": "c1"}}`, "payload.order_id: must be of type integer."},
+		{"Minimum", `{"order_id": 0, "customer": {"id": "c1"}}`, "payload.order_id: must be no less than 1."},
+		{"Enum", `{"order_id": 1, "status": "open", "customer": {"id": "c1"}}`, "payload.status: must be one of the allowed values."},
+		{"Const", `{"order_id": 1, "version": 2, "customer": {"id": "c1"}}`, "payload.version: must be equal to 1."},
+		{"Min length", `{"order_id": 1, "customer": {"id": "c"}}`, "payload.customer.id: the length must be no less than 2."},
+		{"Pattern", `{"order_id": 1, "customer": {"id": "c1", "email": "invalid"}}`, "payload.customer.email: must be in a valid format."},
+		{"Min items", `{"order_id": 1, "customer": {"id": "c1"}, "items": []}`, "payload.items: must contain at least 1 items."},
+		{"Array items", `{"order_id": 1, "customer": {"id": "c1"}, "items": [{"sku-id": "abcd"}]}`, `payload.items[0]["sku-id"]: the length must be no more than 3.`},
+		{"Exclusive minimum", `{"order_id": 1, "customer": {"id": "c1"}, "amount": 0}`, "payload.amount: must be greater than 0."},
+		{"Additional properties", `{"order_id": 1, "customer": {"id": "c1"}, "coupon": "A"}`, "payload.coupon: is not allowed."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			err := ValidatePayloadSchema(schema, tt.payload)
+			if tt.expectedError == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.IsType(t, &PayloadValidationError{}, err)
+			assert.Equal(t, tt.expectedError, err.Error())
+		})
+	}
+
+	t.Run("Empty schema", func(t *testing.T) {
+		assert.Nil(t, ValidatePayloadSchema("", `order_id=1234`))
+	})
+}
+
+func TestParsePayloadSchema(t *testing.T) {
+	var tests = []struct {
+		kind          string
+		schema        string
+		expectedError string
+	}{
+		{"Not JSON", `{`, "invalid schema: unexpected end of JSON input"},
+		{"Unsupported type", `{"properties": {"id": {"type": "uuid"}}}`, `schema.properties.id: unsupported type "uuid"`},
+		{"Invalid type", `{"type": 1}`, "invalid schema: type must be a string or an array of strings"},
+		{"Invalid pattern", `{"pattern": "["}`, "schema: invalid pattern: error parsing regexp: missing closing ]: `[`"},
+		{"Unsupported keyword", `{"oneOf": [{"type": "string"}, {"type": "integer"}]}`, `schema: unsupported keyword "oneOf"`},
+		{"Nested unsupported keyword", `{"properties": {"customer": {"$ref": "#/definitions/customer"}}}`, `schema.properties.customer: unsupported keyword "$ref"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			_, err := ParsePayloadSchema(tt.schema)
+			assert.Equal(t, tt.expectedError, err.Error())
+		})
+	}
+}
@@ -11,6 +11,7 @@ type WorkerService interface {
 // MigrationService is the interface that will be used to execute database migrations.
 type MigrationService interface {
 	Run(ctx context.Context) error
+	BackfillPayloadJSON(ctx context.Context, batchSize int) error
 }
 
 // WebhookService is the interface that will be used to perform operations with webhooks.
@@ -20,15 +21,22 @@ type WebhookService interface {
 	Create(ctx context.Context, webhook *Webhook) error
 	Update(ctx context.Context, webhook *Webhook) error
 	Delete(ctx context.Context, id ID) error
+	Test(ctx context.Context, id ID, payload string) (*WebhookTestResult, error)
+	Verify(ctx context.Context, id ID) (*Webhook, error)
+	FilterDryRun(ctx context.Context, filterDryRun *WebhookFilterDryRun) error
+	Preview(ctx context.Context, id ID, payload string) (*WebhookPreview, error)
 }
 
 // DeliveryService is the interface that will be used to perform operations with deliveries.
 type DeliveryService interface {
 	Get(ctx context.Context, getOptions RepositoryGetOptions) (*Delivery, error)
 	List(ctx context.Context, listOptions RepositoryListOptions) ([]*Delivery, error)
-	Create(ctx context.Context, delivery *Delivery) error
+	Create(ctx context.Context, delivery *Delivery, useWebhookPriority bool) (bool, error)
 	Update(ctx context.Context, delivery *Delivery) error
 	Delete(ctx context.Context, id ID) error
+	Cancel(ctx context.Context, id ID) (*Delivery, error)
+	Retry(ctx context.Context, id ID, resetDeliveryAttempts bool) (*Delivery, error)
+	ReplayToURL(ctx context.Context, id ID, url string) (*DeliveryAttempt, error)
 }
 
 // DeliveryAttemptService is the interface that will be used to perform operations with delivery attempt.
@@ -37,6 +45,30 @@ type DeliveryAttemptService interface {
 	List(ctx context.Context, listOptions RepositoryListOptions) ([]*DeliveryAttempt, error)
 }
 
+// ReplayJobService is the interface that will be used to perform operations with replay jobs.
+type ReplayJobService interface {
+	Get(ctx context.Context, getOptions RepositoryGetOptions) (*ReplayJob, error)
+	List(ctx context.Context, listOptions RepositoryListOptions) ([]*ReplayJob, error)
+	Create(ctx context.Context, replayJob *ReplayJob) error
+	Run(ctx context.Context, id ID) (*ReplayJob, error)
+}
+
+// EventService is the interface that will be used to perform operations with events.
+type EventService interface {
+	Get(ctx context.Context, getOptions RepositoryGetOptions) (*Event, error)
+	List(ctx context.Context, listOptions RepositoryListOptions) ([]*Event, error)
+	Create(ctx context.Context, event *Event) error
+}
+
+// DeadLetterService is the interface that will be used to perform operations with dead-lettered deliveries.
+type DeadLetterService interface {
+	Get(ctx context.Context, getOptions RepositoryGetOptions) (*DeadLetter, error)
+	List(ctx context.Context, listOptions RepositoryListOptions) ([]*DeadLetter, error)
+	Stats(ctx context.Context, listOptions RepositoryListOptions) ([]*DeadLetterStats, error)
+	Replay(ctx context.Context, id ID, resetDeliveryAttempts bool) (*Delivery, error)
+	Discard(ctx context.Context, id ID) (*Delivery, error)
+}
+
 // PingService is the interface that will be used to perform ping operation against database.
 type PingService interface {
 	Run(ctx context.Context) error
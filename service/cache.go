@@ -0,0 +1,40 @@
+package service
+
+import "sync"
+
+// parseCacheMaxSize is the max amount of parsed values kept by a parseCache.
+const parseCacheMaxSize = 1000
+
+// parseCache keeps values parsed from a source string (eg: webhook filter expressions and payload schemas),
+// the cache is cleared when it reaches parseCacheMaxSize so the values of changed or deleted webhooks are released.
+// Parse errors are not cached.
+type parseCache struct {
+	mu     sync.Mutex
+	values map[string]interface{}
+	parse  func(source string) (interface{}, error)
+}
+
+func (c *parseCache) get(source string) (interface{}, error) {
+	c.mu.Lock()
+	value, ok := c.values[source]
+	c.mu.Unlock()
+	if ok {
+		return value, nil
+	}
+
+	value, err := c.parse(source)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if len(c.values) >= parseCacheMaxSize {
+		c.values = map[string]interface{}{}
+	}
+	c.values[source] = value
+	c.mu.Unlock()
+	return value, nil
+}
+
+func newParseCache(parse func(source string) (interface{}, error)) *parseCache {
+	return &parseCache{values: map[string]interface{}{}, parse: parse}
+}
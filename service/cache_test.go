@@ -0,0 +1,49 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCache(t *testing.T) {
+	t.Run("Cached value", func(t *testing.T) {
+		parses := 0
+		cache := newParseCache(func(source string) (interface{}, error) {
+			parses++
+			return len(source), nil
+		})
+		for i := 0; i < 2; i++ {
+			value, err := cache.get("source")
+			assert.Nil(t, err)
+			assert.Equal(t, 6, value)
+		}
+		assert.Equal(t, 1, parses)
+	})
+
+	t.Run("Parse error is not cached", func(t *testing.T) {
+		parses := 0
+		cache := newParseCache(func(source string) (interface{}, error) {
+			parses++
+			return nil, errors.New("invalid source")
+		})
+		for i := 0; i < 2; i++ {
+			_, err := cache.get("source")
+			assert.Equal(t, "invalid source", err.Error())
+		}
+		assert.Equal(t, 2, parses)
+	})
+
+	t.Run("Cleared when full", func(t *testing.T) {
+		cache := newParseCache(func(source string) (interface{}, error) {
+			return source, nil
+		})
+		for i := 0; i <= parseCacheMaxSize; i++ {
+			_, err := cache.get(fmt.Sprintf("source-%d", i))
+			assert.Nil(t, err)
+		}
+		assert.Len(t, cache.values, 1)
+	})
+}
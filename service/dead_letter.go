@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	"github.com/allisson/postmand"
+)
+
+// DeadLetter implements postmand.DeadLetterService interface.
+type DeadLetter struct {
+	deadLetterRepository postmand.DeadLetterRepository
+}
+
+// Get returns postmand.DeadLetter by options filter.
+func (d DeadLetter) Get(ctx context.Context, getOptions postmand.RepositoryGetOptions) (*postmand.DeadLetter, error) {
+	return d.deadLetterRepository.Get(ctx, getOptions)
+}
+
+// List returns a slice of postmand.DeadLetter by options filter.
+func (d DeadLetter) List(ctx context.Context, listOptions postmand.RepositoryListOptions) ([]*postmand.DeadLetter, error) {
+	return d.deadLetterRepository.List(ctx, listOptions)
+}
+
+// Stats returns a slice of postmand.DeadLetterStats by options filter.
+func (d DeadLetter) Stats(ctx context.Context, listOptions postmand.RepositoryListOptions) ([]*postmand.DeadLetterStats, error) {
+	return d.deadLetterRepository.Stats(ctx, listOptions)
+}
+
+// Replay sends again a dead-lettered postmand.Delivery.
+func (d DeadLetter) Replay(ctx context.Context, id postmand.ID, resetDeliveryAttempts bool) (*postmand.Delivery, error) {
+	return d.deadLetterRepository.Replay(ctx, id, resetDeliveryAttempts)
+}
+
+// Discard removes a postmand.Delivery from the dead-letter queue.
+func (d DeadLetter) Discard(ctx context.Context, id postmand.ID) (*postmand.Delivery, error) {
+	return d.deadLetterRepository.Discard(ctx, id)
+}
+
+// NewDeadLetter will create an implementation of postmand.DeadLetterService.
+func NewDeadLetter(deadLetterRepository postmand.DeadLetterRepository) *DeadLetter {
+	return &DeadLetter{deadLetterRepository: deadLetterRepository}
+}
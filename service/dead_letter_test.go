@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/allisson/postmand"
+	"github.com/allisson/postmand/mocks"
+)
+
+func TestDeadLetter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Get", func(t *testing.T) {
+		deadLetterRepository := &mocks.DeadLetterRepository{}
+		deadLetterService := NewDeadLetter(deadLetterRepository)
+		expectedDeadLetter := &postmand.DeadLetter{Delivery: postmand.Delivery{ID: uuid.New()}}
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": expectedDeadLetter.ID}}
+
+		deadLetterRepository.On("Get", mock.Anything, getOptions).Return(expectedDeadLetter, nil)
+		deadLetter, err := deadLetterService.Get(ctx, getOptions)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedDeadLetter, deadLetter)
+		deadLetterRepository.AssertExpectations(t)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		deadLetterRepository := &mocks.DeadLetterRepository{}
+		deadLetterService := NewDeadLetter(deadLetterRepository)
+		expectedDeadLetter := &postmand.DeadLetter{Delivery: postmand.Delivery{ID: uuid.New()}}
+		listOptions := postmand.RepositoryListOptions{Filters: map[string]interface{}{"id": expectedDeadLetter.ID}, Limit: 1, Offset: 0}
+
+		deadLetterRepository.On("List", mock.Anything, listOptions).Return([]*postmand.DeadLetter{expectedDeadLetter}, nil)
+		deadLetters, err := deadLetterService.List(ctx, listOptions)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedDeadLetter, deadLetters[0])
+		deadLetterRepository.AssertExpectations(t)
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		deadLetterRepository := &mocks.DeadLetterRepository{}
+		deadLetterService := NewDeadLetter(deadLetterRepository)
+		expectedDeadLetterStats := &postmand.DeadLetterStats{WebhookID: uuid.New(), Deliveries: 2}
+		listOptions := postmand.RepositoryListOptions{Filters: map[string]interface{}{}, Limit: 1, Offset: 0}
+
+		deadLetterRepository.On("Stats", mock.Anything, listOptions).Return([]*postmand.DeadLetterStats{expectedDeadLetterStats}, nil)
+		deadLetterStats, err := deadLetterService.Stats(ctx, listOptions)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedDeadLetterStats, deadLetterStats[0])
+		deadLetterRepository.AssertExpectations(t)
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		deadLetterRepository := &mocks.DeadLetterRepository{}
+		deadLetterService := NewDeadLetter(deadLetterRepository)
+		expectedDelivery := &postmand.Delivery{ID: uuid.New(), Status: postmand.DeliveryStatusPending}
+
+		deadLetterRepository.On("Replay", mock.Anything, expectedDelivery.ID, true).Return(expectedDelivery, nil)
+		delivery, err := deadLetterService.Replay(ctx, expectedDelivery.ID, true)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedDelivery, delivery)
+		deadLetterRepository.AssertExpectations(t)
+	})
+
+	t.Run("Discard", func(t *testing.T) {
+		deadLetterRepository := &mocks.DeadLetterRepository{}
+		deadLetterService := NewDeadLetter(deadLetterRepository)
+		expectedDelivery := &postmand.Delivery{ID: uuid.New(), Status: postmand.DeliveryStatusDiscarded}
+
+		deadLetterRepository.On("Discard", mock.Anything, expectedDelivery.ID).Return(expectedDelivery, nil)
+		delivery, err := deadLetterService.Discard(ctx, expectedDelivery.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedDelivery, delivery)
+		deadLetterRepository.AssertExpectations(t)
+	})
+}
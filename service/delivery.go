@@ -11,7 +11,11 @@ import (
 
 // Delivery implements postmand.DeliveryService interface.
 type Delivery struct {
-	deliveryRepository postmand.DeliveryRepository
+	deliveryRepository      postmand.DeliveryRepository
+	webhookRepository       postmand.WebhookRepository
+	idempotencyKeyRetention time.Duration
+	maxPayloadSize          int
+	payloadSchemas          *parseCache
 }
 
 // Get returns postmand.Delivery by options filter.
@@ -24,15 +28,127 @@ func (d Delivery) List(ctx context.Context, listOptions postmand.RepositoryListO
 	return d.deliveryRepository.List(ctx, listOptions)
 }
 
-// Create postmand.Delivery on database.
-func (d Delivery) Create(ctx context.Context, delivery *postmand.Delivery) error {
-	now := time.Now().UTC()
+// Create postmand.Delivery on database, the original delivery is returned if the idempotency key was already used.
+// The webhook priority replaces the delivery priority when useWebhookPriority is true.
+// Returns true if a new delivery was created and false if the original delivery was returned.
+// Returns a *postmand.PayloadValidationError if the payload is too large or does not conform to the webhook content type
+// or payload schema.
+func (d Delivery) Create(ctx context.Context, delivery *postmand.Delivery, useWebhookPriority bool) (bool, error) {
+	getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": delivery.WebhookID}}
+	webhook, err := d.webhookRepository.Get(ctx, getOptions)
+	if err != nil {
+		return false, err
+	}
+	if err := d.validatePayload(webhook, delivery); err != nil {
+		return false, err
+	}
+
+	if delivery.IdempotencyKey != "" {
+		originalDelivery, err := d.getByIdempotencyKey(ctx, delivery.WebhookID, delivery.IdempotencyKey)
+		if err == nil {
+			*delivery = *originalDelivery
+			return false, nil
+		}
+		if err != postmand.ErrDeliveryNotFound {
+			return false, err
+		}
+	}
+
+	prepareDelivery(delivery, webhook, useWebhookPriority, time.Now().UTC())
+	err = d.deliveryRepository.Create(ctx, delivery)
+	if err != postmand.ErrDeliveryIdempotencyKeyConflict {
+		return err == nil, err
+	}
+
+	// A concurrent request created the delivery with the same idempotency key
+	originalDelivery, err := d.getByIdempotencyKey(ctx, delivery.WebhookID, delivery.IdempotencyKey)
+	if err != nil {
+		return false, err
+	}
+	*delivery = *originalDelivery
+	return false, nil
+}
+
+func parsePayloadSchema(schema string) (interface{}, error) {
+	return postmand.ParsePayloadSchema(schema)
+}
+
+// validatePayloadSchema validates the payload against the schema parsed by payloadSchemas, an empty schema accepts any payload.
+func validatePayloadSchema(payloadSchemas *parseCache, schema, payload string) error {
+	if schema == "" {
+		return nil
+	}
+	payloadSchema, err := payloadSchemas.get(schema)
+	if err != nil {
+		return err
+	}
+	return payloadSchema.(*postmand.PayloadSchema).Validate(payload)
+}
+
+// validateRequestBody checks the content type of the body that is sent to the webhook, the payload template output is
+// validated when the webhook has one and a template that can't be rendered with the payload is an error.
+func validateRequestBody(webhook *postmand.Webhook, payload string) error {
+	body, err := postmand.RenderPayload(webhook.PayloadTemplate, payload)
+	if err != nil {
+		return err
+	}
+	return postmand.ValidatePayloadContentType(webhook.ContentType, body)
+}
+
+// prepareDelivery fills the fields of a new pending delivery of the webhook, the scheduled time defaults to now
+// and the expiration is calculated from the webhook delivery_ttl when the delivery does not define one.
+// The webhook priority replaces the delivery priority when useWebhookPriority is true.
+func prepareDelivery(delivery *postmand.Delivery, webhook *postmand.Webhook, useWebhookPriority bool, now time.Time) {
 	delivery.ID = uuid.New()
-	delivery.ScheduledAt = now
+	if useWebhookPriority {
+		delivery.Priority = webhook.Priority
+	}
+	if delivery.ScheduledAt.IsZero() {
+		delivery.ScheduledAt = now
+	}
+	delivery.ScheduledAt = delivery.ScheduledAt.UTC()
+	if delivery.ExpiresAt == nil && webhook.DeliveryTTL > 0 {
+		expiresAt := delivery.ScheduledAt.Add(time.Duration(webhook.DeliveryTTL) * time.Second)
+		delivery.ExpiresAt = &expiresAt
+	}
 	delivery.Status = postmand.DeliveryStatusPending
 	delivery.CreatedAt = now
 	delivery.UpdatedAt = now
-	return d.deliveryRepository.Create(ctx, delivery)
+}
+
+// validatePayload checks the payload size, the payload schema and the content type of the body that is sent to the webhook.
+func (d Delivery) validatePayload(webhook *postmand.Webhook, delivery *postmand.Delivery) error {
+	body, err := delivery.Body()
+	if err != nil {
+		return err
+	}
+	payload := string(body)
+	if err := postmand.ValidatePayloadSize(payload, d.maxPayloadSize); err != nil {
+		return err
+	}
+	if err := validatePayloadSchema(d.payloadSchemas, webhook.PayloadSchema, payload); err != nil {
+		return err
+	}
+	return validateRequestBody(webhook, payload)
+}
+
+// getByIdempotencyKey returns the delivery created with the idempotency key inside the retention window,
+// the key of an older delivery is released and postmand.ErrDeliveryNotFound is returned.
+func (d Delivery) getByIdempotencyKey(ctx context.Context, webhookID postmand.ID, idempotencyKey string) (*postmand.Delivery, error) {
+	getOptions := postmand.RepositoryGetOptions{
+		Filters: map[string]interface{}{"webhook_id": webhookID, "idempotency_key": idempotencyKey},
+	}
+	delivery, err := d.deliveryRepository.Get(ctx, getOptions)
+	if err != nil {
+		return delivery, err
+	}
+	if time.Since(delivery.CreatedAt) <= d.idempotencyKeyRetention {
+		return delivery, nil
+	}
+	if err := d.deliveryRepository.ReleaseIdempotencyKey(ctx, delivery.ID); err != nil {
+		return delivery, err
+	}
+	return delivery, postmand.ErrDeliveryNotFound
 }
 
 // Update postmand.Delivery on database.
@@ -52,7 +168,33 @@ func (d Delivery) Delete(ctx context.Context, id postmand.ID) error {
 	return d.deliveryRepository.Delete(ctx, id)
 }
 
+// Cancel postmand.Delivery that is still pending.
+func (d Delivery) Cancel(ctx context.Context, id postmand.ID) (*postmand.Delivery, error) {
+	return d.deliveryRepository.Cancel(ctx, id)
+}
+
+// Retry postmand.Delivery that is already succeeded or failed, the delivery attempts history is kept.
+func (d Delivery) Retry(ctx context.Context, id postmand.ID, resetDeliveryAttempts bool) (*postmand.Delivery, error) {
+	return d.deliveryRepository.Retry(ctx, id, resetDeliveryAttempts)
+}
+
+// ReplayToURL sends the payload of postmand.Delivery to an alternate url without changing the delivery.
+func (d Delivery) ReplayToURL(ctx context.Context, id postmand.ID, url string) (*postmand.DeliveryAttempt, error) {
+	return d.deliveryRepository.ReplayToURL(ctx, id, url)
+}
+
 // NewDelivery will create an implementation of postmand.DeliveryService.
-func NewDelivery(deliveryRepository postmand.DeliveryRepository) *Delivery {
-	return &Delivery{deliveryRepository: deliveryRepository}
+func NewDelivery(
+	deliveryRepository postmand.DeliveryRepository,
+	webhookRepository postmand.WebhookRepository,
+	idempotencyKeyRetention time.Duration,
+	maxPayloadSize int,
+) *Delivery {
+	return &Delivery{
+		deliveryRepository:      deliveryRepository,
+		webhookRepository:       webhookRepository,
+		idempotencyKeyRetention: idempotencyKeyRetention,
+		maxPayloadSize:          maxPayloadSize,
+		payloadSchemas:          newParseCache(parsePayloadSchema),
+	}
 }
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -17,7 +18,8 @@ func TestDelivery(t *testing.T) {
 
 	t.Run("Get", func(t *testing.T) {
 		deliveryRepository := &mocks.DeliveryRepository{}
-		deliveryService := NewDelivery(deliveryRepository)
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
 		expectedDelivery := &postmand.Delivery{ID: uuid.New()}
 		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": expectedDelivery.ID}}
 
@@ -30,7 +32,8 @@ func TestDelivery(t *testing.T) {
 
 	t.Run("List", func(t *testing.T) {
 		deliveryRepository := &mocks.DeliveryRepository{}
-		webhookService := NewDelivery(deliveryRepository)
+		webhookRepository := &mocks.WebhookRepository{}
+		webhookService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
 		expectedDelivery := &postmand.Delivery{ID: uuid.New()}
 		listOptions := postmand.RepositoryListOptions{Filters: map[string]interface{}{"id": expectedDelivery.ID}, Limit: 1, Offset: 0}
 
@@ -43,18 +46,303 @@ func TestDelivery(t *testing.T) {
 
 	t.Run("Create", func(t *testing.T) {
 		deliveryRepository := &mocks.DeliveryRepository{}
-		webhookService := NewDelivery(deliveryRepository)
-		delivery := &postmand.Delivery{ID: uuid.New()}
+		webhookRepository := &mocks.WebhookRepository{}
+		webhookService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
+		webhook := &postmand.Webhook{ID: uuid.New()}
+		delivery := &postmand.Delivery{ID: uuid.New(), WebhookID: webhook.ID}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
+		deliveryRepository.On("Create", mock.Anything, delivery).Return(nil)
+		created, err := webhookService.Create(ctx, delivery, true)
+		assert.Nil(t, err)
+		assert.True(t, created)
+		deliveryRepository.AssertExpectations(t)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with invalid payload", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
+		webhook := &postmand.Webhook{ID: uuid.New(), PayloadSchema: `{"type": "object", "required": ["order_id"]}`}
+		delivery := &postmand.Delivery{ID: uuid.New(), WebhookID: webhook.ID, Payload: `{"amount": 10}`}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
+		_, err := deliveryService.Create(ctx, delivery, true)
+		assert.Equal(t, "payload.order_id: is required.", err.Error())
+		assert.IsType(t, &postmand.PayloadValidationError{}, err)
+		deliveryRepository.AssertExpectations(t)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with payload that does not match the content type", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
+		webhook := &postmand.Webhook{ID: uuid.New(), ContentType: "application/json"}
+		delivery := &postmand.Delivery{ID: uuid.New(), WebhookID: webhook.ID, Payload: `order_id=1`}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
+		_, err := deliveryService.Create(ctx, delivery, true)
+		assert.Equal(t, "payload: must be a valid JSON.", err.Error())
+		deliveryRepository.AssertExpectations(t)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with payload template and content type", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
+		webhook := &postmand.Webhook{ID: uuid.New(), ContentType: "application/xml", PayloadTemplate: `<order><id>{{ .id }}</id></order>`}
+		delivery := &postmand.Delivery{ID: uuid.New(), WebhookID: webhook.ID, Payload: `{"id": 1}`}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
+		deliveryRepository.On("Create", mock.Anything, delivery).Return(nil)
+		_, err := deliveryService.Create(ctx, delivery, true)
+		assert.Nil(t, err)
+		deliveryRepository.AssertExpectations(t)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with payload template error", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
+		webhook := &postmand.Webhook{ID: uuid.New(), ContentType: "application/xml", PayloadTemplate: `<order><id>{{ .id }}</id></order>`}
+		delivery := &postmand.Delivery{ID: uuid.New(), WebhookID: webhook.ID, Payload: `{"order_id": 1}`}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
+		_, err := deliveryService.Create(ctx, delivery, true)
+		assert.IsType(t, &postmand.PayloadValidationError{}, err)
+		assert.Contains(t, err.Error(), "payload: cannot be rendered by the payload template:")
+		deliveryRepository.AssertExpectations(t)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with payload too large", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, 8)
+		webhook := &postmand.Webhook{ID: uuid.New()}
+		delivery := &postmand.Delivery{ID: uuid.New(), WebhookID: webhook.ID, Payload: `{"id": 1234}`}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
+		_, err := deliveryService.Create(ctx, delivery, true)
+		assert.Equal(t, "payload: the size must be no more than 8 bytes.", err.Error())
+		deliveryRepository.AssertExpectations(t)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with base64 payload", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, 4)
+		webhook := &postmand.Webhook{ID: uuid.New(), ContentType: "application/octet-stream"}
+		delivery := &postmand.Delivery{ID: uuid.New(), WebhookID: webhook.ID, Payload: "/wABAg==", PayloadEncoding: postmand.DeliveryPayloadEncodingBase64}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
+		deliveryRepository.On("Create", mock.Anything, delivery).Return(nil)
+		_, err := deliveryService.Create(ctx, delivery, true)
+		assert.Nil(t, err)
+		deliveryRepository.AssertExpectations(t)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with webhook priority", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
+		webhook := &postmand.Webhook{ID: uuid.New(), Priority: 10}
+		delivery := &postmand.Delivery{ID: uuid.New(), WebhookID: webhook.ID}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
+		deliveryRepository.On("Create", mock.Anything, delivery).Return(nil)
+		_, err := deliveryService.Create(ctx, delivery, true)
+		assert.Nil(t, err)
+		assert.Equal(t, 10, delivery.Priority)
+		deliveryRepository.AssertExpectations(t)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with delivery priority", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
+		webhook := &postmand.Webhook{ID: uuid.New(), Priority: 10}
+		delivery := &postmand.Delivery{ID: uuid.New(), WebhookID: webhook.ID, Priority: 50}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
+		deliveryRepository.On("Create", mock.Anything, delivery).Return(nil)
+		_, err := deliveryService.Create(ctx, delivery, false)
+		assert.Nil(t, err)
+		assert.Equal(t, 50, delivery.Priority)
+		deliveryRepository.AssertExpectations(t)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with delivery priority zero", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
+		webhook := &postmand.Webhook{ID: uuid.New(), Priority: 10}
+		delivery := &postmand.Delivery{ID: uuid.New(), WebhookID: webhook.ID, Priority: 0}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
+		deliveryRepository.On("Create", mock.Anything, delivery).Return(nil)
+		_, err := deliveryService.Create(ctx, delivery, false)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, delivery.Priority)
+		deliveryRepository.AssertExpectations(t)
+		webhookRepository.AssertExpectations(t)
+	})
 
+	t.Run("Create with webhook delivery ttl", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
+		webhook := &postmand.Webhook{ID: uuid.New(), DeliveryTTL: 60}
+		delivery := &postmand.Delivery{ID: uuid.New(), WebhookID: webhook.ID}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
 		deliveryRepository.On("Create", mock.Anything, delivery).Return(nil)
-		err := webhookService.Create(ctx, delivery)
+		_, err := deliveryService.Create(ctx, delivery, true)
 		assert.Nil(t, err)
+		assert.Equal(t, delivery.ScheduledAt.Add(60*time.Second), *delivery.ExpiresAt)
 		deliveryRepository.AssertExpectations(t)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with scheduled_at", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
+		webhook := &postmand.Webhook{ID: uuid.New()}
+		scheduledAt := time.Now().UTC().Add(time.Hour)
+		delivery := &postmand.Delivery{ID: uuid.New(), WebhookID: webhook.ID, ScheduledAt: scheduledAt}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
+		deliveryRepository.On("Create", mock.Anything, delivery).Return(nil)
+		_, err := deliveryService.Create(ctx, delivery, true)
+		assert.Nil(t, err)
+		assert.Equal(t, scheduledAt, delivery.ScheduledAt)
+		assert.Nil(t, delivery.ExpiresAt)
+		deliveryRepository.AssertExpectations(t)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with idempotency key", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
+		webhook := &postmand.Webhook{ID: uuid.New()}
+		delivery := &postmand.Delivery{WebhookID: webhook.ID, IdempotencyKey: "key"}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
+		idempotencyKeyGetOptions := postmand.RepositoryGetOptions{
+			Filters: map[string]interface{}{"webhook_id": webhook.ID, "idempotency_key": "key"},
+		}
+		deliveryRepository.On("Get", mock.Anything, idempotencyKeyGetOptions).Return(&postmand.Delivery{}, postmand.ErrDeliveryNotFound)
+		deliveryRepository.On("Create", mock.Anything, delivery).Return(nil)
+		created, err := deliveryService.Create(ctx, delivery, true)
+		assert.Nil(t, err)
+		assert.True(t, created)
+		assert.Equal(t, "key", delivery.IdempotencyKey)
+		deliveryRepository.AssertExpectations(t)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with used idempotency key", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
+		webhook := &postmand.Webhook{ID: uuid.New()}
+		originalDelivery := &postmand.Delivery{ID: uuid.New(), WebhookID: webhook.ID, IdempotencyKey: "key", CreatedAt: time.Now().UTC()}
+		delivery := &postmand.Delivery{WebhookID: webhook.ID, IdempotencyKey: "key"}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
+		idempotencyKeyGetOptions := postmand.RepositoryGetOptions{
+			Filters: map[string]interface{}{"webhook_id": webhook.ID, "idempotency_key": "key"},
+		}
+		deliveryRepository.On("Get", mock.Anything, idempotencyKeyGetOptions).Return(originalDelivery, nil)
+		created, err := deliveryService.Create(ctx, delivery, true)
+		assert.Nil(t, err)
+		assert.False(t, created)
+		assert.Equal(t, originalDelivery, delivery)
+		deliveryRepository.AssertExpectations(t)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with expired idempotency key", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, time.Hour, postmand.DeliveryPayloadMaxSize)
+		webhook := &postmand.Webhook{ID: uuid.New()}
+		originalDelivery := &postmand.Delivery{
+			ID:             uuid.New(),
+			WebhookID:      webhook.ID,
+			IdempotencyKey: "key",
+			CreatedAt:      time.Now().UTC().Add(-2 * time.Hour),
+		}
+		delivery := &postmand.Delivery{WebhookID: webhook.ID, IdempotencyKey: "key"}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
+		idempotencyKeyGetOptions := postmand.RepositoryGetOptions{
+			Filters: map[string]interface{}{"webhook_id": webhook.ID, "idempotency_key": "key"},
+		}
+		deliveryRepository.On("Get", mock.Anything, idempotencyKeyGetOptions).Return(originalDelivery, nil)
+		deliveryRepository.On("ReleaseIdempotencyKey", mock.Anything, originalDelivery.ID).Return(nil)
+		deliveryRepository.On("Create", mock.Anything, delivery).Return(nil)
+		created, err := deliveryService.Create(ctx, delivery, true)
+		assert.Nil(t, err)
+		assert.True(t, created)
+		assert.NotEqual(t, originalDelivery.ID, delivery.ID)
+		deliveryRepository.AssertExpectations(t)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with concurrent idempotency key", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
+		webhook := &postmand.Webhook{ID: uuid.New()}
+		originalDelivery := &postmand.Delivery{ID: uuid.New(), WebhookID: webhook.ID, IdempotencyKey: "key", CreatedAt: time.Now().UTC()}
+		delivery := &postmand.Delivery{WebhookID: webhook.ID, IdempotencyKey: "key"}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
+		idempotencyKeyGetOptions := postmand.RepositoryGetOptions{
+			Filters: map[string]interface{}{"webhook_id": webhook.ID, "idempotency_key": "key"},
+		}
+		deliveryRepository.On("Get", mock.Anything, idempotencyKeyGetOptions).Return(&postmand.Delivery{}, postmand.ErrDeliveryNotFound).Once()
+		deliveryRepository.On("Create", mock.Anything, delivery).Return(postmand.ErrDeliveryIdempotencyKeyConflict)
+		deliveryRepository.On("Get", mock.Anything, idempotencyKeyGetOptions).Return(originalDelivery, nil).Once()
+		created, err := deliveryService.Create(ctx, delivery, true)
+		assert.Nil(t, err)
+		assert.False(t, created)
+		assert.Equal(t, originalDelivery, delivery)
+		deliveryRepository.AssertExpectations(t)
+		webhookRepository.AssertExpectations(t)
 	})
 
 	t.Run("Update", func(t *testing.T) {
 		deliveryRepository := &mocks.DeliveryRepository{}
-		webhookService := NewDelivery(deliveryRepository)
+		webhookRepository := &mocks.WebhookRepository{}
+		webhookService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
 		delivery := &postmand.Delivery{ID: uuid.New()}
 
 		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": delivery.ID}}
@@ -67,7 +355,8 @@ func TestDelivery(t *testing.T) {
 
 	t.Run("Delete", func(t *testing.T) {
 		deliveryRepository := &mocks.DeliveryRepository{}
-		webhookService := NewDelivery(deliveryRepository)
+		webhookRepository := &mocks.WebhookRepository{}
+		webhookService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
 		delivery := &postmand.Delivery{ID: uuid.New()}
 
 		deliveryRepository.On("Delete", mock.Anything, delivery.ID).Return(nil)
@@ -75,4 +364,42 @@ func TestDelivery(t *testing.T) {
 		assert.Nil(t, err)
 		deliveryRepository.AssertExpectations(t)
 	})
+	t.Run("Cancel", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
+		expectedDelivery := &postmand.Delivery{ID: uuid.New(), Status: postmand.DeliveryStatusCancelled}
+
+		deliveryRepository.On("Cancel", mock.Anything, expectedDelivery.ID).Return(expectedDelivery, nil)
+		delivery, err := deliveryService.Cancel(ctx, expectedDelivery.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedDelivery, delivery)
+		deliveryRepository.AssertExpectations(t)
+	})
+	t.Run("Retry", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
+		expectedDelivery := &postmand.Delivery{ID: uuid.New(), Status: postmand.DeliveryStatusPending}
+
+		deliveryRepository.On("Retry", mock.Anything, expectedDelivery.ID, true).Return(expectedDelivery, nil)
+		delivery, err := deliveryService.Retry(ctx, expectedDelivery.ID, true)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedDelivery, delivery)
+		deliveryRepository.AssertExpectations(t)
+	})
+
+	t.Run("ReplayToURL", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		deliveryService := NewDelivery(deliveryRepository, webhookRepository, postmand.DeliveryIdempotencyKeyRetention, postmand.DeliveryPayloadMaxSize)
+		deliveryID := uuid.New()
+		expectedDeliveryAttempt := &postmand.DeliveryAttempt{ID: uuid.New(), DeliveryID: deliveryID, Kind: postmand.DeliveryAttemptKindReplay}
+
+		deliveryRepository.On("ReplayToURL", mock.Anything, deliveryID, "http://localhost:9000").Return(expectedDeliveryAttempt, nil)
+		deliveryAttempt, err := deliveryService.ReplayToURL(ctx, deliveryID, "http://localhost:9000")
+		assert.Nil(t, err)
+		assert.Equal(t, expectedDeliveryAttempt, deliveryAttempt)
+		deliveryRepository.AssertExpectations(t)
+	})
 }
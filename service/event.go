@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/allisson/postmand"
+)
+
+// filterWebhooks returns the webhooks without a filter expression and the ones whose filter expression matches the payload,
+// a payload that is not a valid JSON only matches webhooks without a filter expression.
+// Webhooks whose payload schema rejects the payload or that can't send the payload with their payload template
+// and content type are skipped.
+func filterWebhooks(webhooks []*postmand.Webhook, payload string, filterExpressions, payloadSchemas *parseCache) []*postmand.Webhook {
+	var decodedPayload interface{}
+	validPayload := json.Unmarshal([]byte(payload), &decodedPayload) == nil
+	filteredWebhooks := []*postmand.Webhook{}
+	for _, webhook := range webhooks {
+		if validatePayloadSchema(payloadSchemas, webhook.PayloadSchema, payload) != nil {
+			continue
+		}
+		if validateRequestBody(webhook, payload) != nil {
+			continue
+		}
+		if webhook.FilterExpression == "" {
+			filteredWebhooks = append(filteredWebhooks, webhook)
+			continue
+		}
+		if !validPayload {
+			continue
+		}
+		filterExpression, err := filterExpressions.get(webhook.FilterExpression)
+		if err != nil {
+			continue
+		}
+		if filterExpression.(*postmand.FilterExpression).Evaluate(decodedPayload) {
+			filteredWebhooks = append(filteredWebhooks, webhook)
+		}
+	}
+	return filteredWebhooks
+}
+
+func parseFilterExpression(expression string) (interface{}, error) {
+	return postmand.ParseFilterExpression(expression)
+}
+
+// Event implements postmand.EventService interface.
+type Event struct {
+	eventRepository   postmand.EventRepository
+	webhookRepository postmand.WebhookRepository
+	maxPayloadSize    int
+	filterExpressions *parseCache
+	payloadSchemas    *parseCache
+}
+
+// Get returns postmand.Event by options filter.
+func (e Event) Get(ctx context.Context, getOptions postmand.RepositoryGetOptions) (*postmand.Event, error) {
+	return e.eventRepository.Get(ctx, getOptions)
+}
+
+// List returns a slice of postmand.Event by options filter.
+func (e Event) List(ctx context.Context, listOptions postmand.RepositoryListOptions) ([]*postmand.Event, error) {
+	return e.eventRepository.List(ctx, listOptions)
+}
+
+// Create postmand.Event on database with one delivery for each active webhook subscribed to the event type
+// whose filter expression, payload schema, payload template and content type accept the payload.
+// Returns a *postmand.PayloadValidationError if the payload is too large, no delivery is created in this case.
+func (e Event) Create(ctx context.Context, event *postmand.Event) error {
+	if err := postmand.ValidatePayloadSize(event.Payload, e.maxPayloadSize); err != nil {
+		return err
+	}
+	webhooks, err := e.webhookRepository.ListSubscribed(ctx, event.EventType)
+	if err != nil {
+		return err
+	}
+	webhooks = filterWebhooks(webhooks, event.Payload, e.filterExpressions, e.payloadSchemas)
+
+	event.ID = uuid.New()
+	event.Deliveries = len(webhooks)
+	event.CreatedAt = time.Now().UTC()
+	deliveries := make([]*postmand.Delivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		delivery := &postmand.Delivery{
+			WebhookID: webhook.ID,
+			Payload:   event.Payload,
+			EventID:   &event.ID,
+			EventType: event.EventType,
+		}
+		prepareDelivery(delivery, webhook, true, event.CreatedAt)
+		deliveries = append(deliveries, delivery)
+	}
+	return e.eventRepository.Create(ctx, event, deliveries)
+}
+
+// NewEvent will create an implementation of postmand.EventService.
+func NewEvent(eventRepository postmand.EventRepository, webhookRepository postmand.WebhookRepository, maxPayloadSize int) *Event {
+	return &Event{
+		eventRepository:   eventRepository,
+		webhookRepository: webhookRepository,
+		maxPayloadSize:    maxPayloadSize,
+		filterExpressions: newParseCache(parseFilterExpression),
+		payloadSchemas:    newParseCache(parsePayloadSchema),
+	}
+}
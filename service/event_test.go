@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/allisson/postmand"
+	"github.com/allisson/postmand/mocks"
+)
+
+func TestEvent(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Get", func(t *testing.T) {
+		eventRepository := &mocks.EventRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		eventService := NewEvent(eventRepository, webhookRepository, 0)
+		expectedEvent := &postmand.Event{ID: uuid.New()}
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": expectedEvent.ID}}
+
+		eventRepository.On("Get", mock.Anything, getOptions).Return(expectedEvent, nil)
+		event, err := eventService.Get(ctx, getOptions)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedEvent, event)
+		eventRepository.AssertExpectations(t)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		eventRepository := &mocks.EventRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		eventService := NewEvent(eventRepository, webhookRepository, 0)
+		expectedEvent := &postmand.Event{ID: uuid.New()}
+		listOptions := postmand.RepositoryListOptions{Filters: map[string]interface{}{"id": expectedEvent.ID}, Limit: 1, Offset: 0}
+
+		eventRepository.On("List", mock.Anything, listOptions).Return([]*postmand.Event{expectedEvent}, nil)
+		events, err := eventService.List(ctx, listOptions)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedEvent, events[0])
+		eventRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		eventRepository := &mocks.EventRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		eventService := NewEvent(eventRepository, webhookRepository, 0)
+		webhook1 := &postmand.Webhook{ID: uuid.New(), Priority: 10, DeliveryTTL: 60}
+		webhook2 := &postmand.Webhook{ID: uuid.New(), FilterExpression: `payload.country == "AR"`}
+		webhook3 := &postmand.Webhook{ID: uuid.New(), PayloadSchema: `{"type": "object", "required": ["amount"]}`}
+		webhook4 := &postmand.Webhook{ID: uuid.New(), FilterExpression: `payload.country == "BR"`}
+		event := &postmand.Event{EventType: "order.created", Payload: `{"country": "BR"}`}
+		var deliveries []*postmand.Delivery
+
+		webhookRepository.On("ListSubscribed", mock.Anything, "order.created").Return([]*postmand.Webhook{webhook1, webhook2, webhook3, webhook4}, nil)
+		eventRepository.On("Create", mock.Anything, event, mock.Anything).Run(func(args mock.Arguments) {
+			deliveries = args.Get(2).([]*postmand.Delivery)
+		}).Return(nil)
+		err := eventService.Create(ctx, event)
+		assert.Nil(t, err)
+		assert.NotEqual(t, uuid.Nil, event.ID)
+		assert.False(t, event.CreatedAt.IsZero())
+		assert.Equal(t, 2, event.Deliveries)
+		assert.Len(t, deliveries, 2)
+		assert.Equal(t, webhook1.ID, deliveries[0].WebhookID)
+		assert.Equal(t, 10, deliveries[0].Priority)
+		assert.Equal(t, event.CreatedAt.Add(time.Minute), *deliveries[0].ExpiresAt)
+		assert.Equal(t, webhook4.ID, deliveries[1].WebhookID)
+		assert.Equal(t, event.ID, *deliveries[1].EventID)
+		assert.Equal(t, event.EventType, deliveries[1].EventType)
+		assert.Equal(t, event.Payload, deliveries[1].Payload)
+		assert.Equal(t, event.CreatedAt, deliveries[1].ScheduledAt)
+		assert.Equal(t, postmand.DeliveryStatusPending, deliveries[1].Status)
+		assert.Nil(t, deliveries[1].ExpiresAt)
+		webhookRepository.AssertExpectations(t)
+		eventRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create without subscribed webhooks", func(t *testing.T) {
+		eventRepository := &mocks.EventRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		eventService := NewEvent(eventRepository, webhookRepository, 0)
+		event := &postmand.Event{EventType: "order.created", Payload: `{"success": true}`}
+
+		webhookRepository.On("ListSubscribed", mock.Anything, "order.created").Return([]*postmand.Webhook{}, nil)
+		eventRepository.On("Create", mock.Anything, event, []*postmand.Delivery{}).Return(nil)
+		err := eventService.Create(ctx, event)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, event.Deliveries)
+		webhookRepository.AssertExpectations(t)
+		eventRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with payload too large", func(t *testing.T) {
+		eventRepository := &mocks.EventRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		eventService := NewEvent(eventRepository, webhookRepository, 8)
+		event := &postmand.Event{EventType: "order.created", Payload: `{"id": 1234}`}
+
+		err := eventService.Create(ctx, event)
+		assert.IsType(t, &postmand.PayloadValidationError{}, err)
+		assert.Equal(t, "payload: the size must be no more than 8 bytes.", err.Error())
+		webhookRepository.AssertExpectations(t)
+		eventRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create skips webhooks whose content type does not match the payload", func(t *testing.T) {
+		eventRepository := &mocks.EventRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		eventService := NewEvent(eventRepository, webhookRepository, 0)
+		webhook1 := &postmand.Webhook{ID: uuid.New(), ContentType: "text/plain"}
+		webhook2 := &postmand.Webhook{ID: uuid.New(), ContentType: "application/json"}
+		event := &postmand.Event{EventType: "order.created", Payload: `order_id=1`}
+
+		var deliveries []*postmand.Delivery
+
+		webhookRepository.On("ListSubscribed", mock.Anything, "order.created").Return([]*postmand.Webhook{webhook1, webhook2}, nil)
+		eventRepository.On("Create", mock.Anything, event, mock.Anything).Run(func(args mock.Arguments) {
+			deliveries = args.Get(2).([]*postmand.Delivery)
+		}).Return(nil)
+		err := eventService.Create(ctx, event)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, event.Deliveries)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, webhook1.ID, deliveries[0].WebhookID)
+		webhookRepository.AssertExpectations(t)
+		eventRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create skips webhooks whose payload template fails", func(t *testing.T) {
+		eventRepository := &mocks.EventRepository{}
+		webhookRepository := &mocks.WebhookRepository{}
+		eventService := NewEvent(eventRepository, webhookRepository, 0)
+		webhook1 := &postmand.Webhook{ID: uuid.New(), ContentType: "application/xml", PayloadTemplate: `<order><id>{{ .id }}</id></order>`}
+		webhook2 := &postmand.Webhook{ID: uuid.New(), ContentType: "application/json"}
+		event := &postmand.Event{EventType: "order.created", Payload: `{"order_id": 1}`}
+		var deliveries []*postmand.Delivery
+
+		webhookRepository.On("ListSubscribed", mock.Anything, "order.created").Return([]*postmand.Webhook{webhook1, webhook2}, nil)
+		eventRepository.On("Create", mock.Anything, event, mock.Anything).Run(func(args mock.Arguments) {
+			deliveries = args.Get(2).([]*postmand.Delivery)
+		}).Return(nil)
+		err := eventService.Create(ctx, event)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, event.Deliveries)
+		assert.Len(t, deliveries, 1)
+		assert.Equal(t, webhook2.ID, deliveries[0].WebhookID)
+		webhookRepository.AssertExpectations(t)
+		eventRepository.AssertExpectations(t)
+	})
+}
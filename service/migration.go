@@ -24,6 +24,18 @@ func (m Migration) Run(ctx context.Context) error {
 	return nil
 }
 
+// BackfillPayloadJSON copies the JSON payloads of the existing deliveries to the column used by the payload filters.
+func (m Migration) BackfillPayloadJSON(ctx context.Context, batchSize int) error {
+	m.logger.Info("migration-backfill-payload-json-started", zap.Int("batch_size", batchSize))
+	updated, err := m.migrationRepo.BackfillPayloadJSON(ctx, batchSize)
+	if err != nil {
+		m.logger.Error("migration-backfill-payload-json-error", zap.Int("updated_deliveries", updated), zap.Error(err))
+		return err
+	}
+	m.logger.Info("migration-backfill-payload-json-completed", zap.Int("updated_deliveries", updated))
+	return nil
+}
+
 // NewMigration will create an implementation of postmand.MigrationService.
 func NewMigration(migrationRepo postmand.MigrationRepository, logger *zap.Logger) *Migration {
 	return &Migration{
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -13,11 +14,32 @@ import (
 
 func TestMigration(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
-	migrationRepository := &mocks.MigrationRepository{}
-	migrationService := NewMigration(migrationRepository, logger)
-	migrationRepository.On("Run", mock.Anything).Return(nil)
 	ctx := context.Background()
-	err := migrationService.Run(ctx)
-	assert.Nil(t, err)
-	migrationRepository.AssertExpectations(t)
+
+	t.Run("Run", func(t *testing.T) {
+		migrationRepository := &mocks.MigrationRepository{}
+		migrationService := NewMigration(migrationRepository, logger)
+		migrationRepository.On("Run", mock.Anything).Return(nil)
+		err := migrationService.Run(ctx)
+		assert.Nil(t, err)
+		migrationRepository.AssertExpectations(t)
+	})
+
+	t.Run("Backfill payload json", func(t *testing.T) {
+		migrationRepository := &mocks.MigrationRepository{}
+		migrationService := NewMigration(migrationRepository, logger)
+		migrationRepository.On("BackfillPayloadJSON", mock.Anything, 1000).Return(10, nil)
+		err := migrationService.BackfillPayloadJSON(ctx, 1000)
+		assert.Nil(t, err)
+		migrationRepository.AssertExpectations(t)
+	})
+
+	t.Run("Backfill payload json error", func(t *testing.T) {
+		migrationRepository := &mocks.MigrationRepository{}
+		migrationService := NewMigration(migrationRepository, logger)
+		migrationRepository.On("BackfillPayloadJSON", mock.Anything, 1000).Return(5, errors.New("connection reset"))
+		err := migrationService.BackfillPayloadJSON(ctx, 1000)
+		assert.Equal(t, "connection reset", err.Error())
+		migrationRepository.AssertExpectations(t)
+	})
 }
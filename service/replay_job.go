@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/allisson/postmand"
+)
+
+// ReplayJob implements postmand.ReplayJobService interface.
+type ReplayJob struct {
+	replayJobRepository postmand.ReplayJobRepository
+}
+
+// Get returns postmand.ReplayJob by options filter.
+func (r ReplayJob) Get(ctx context.Context, getOptions postmand.RepositoryGetOptions) (*postmand.ReplayJob, error) {
+	return r.replayJobRepository.Get(ctx, getOptions)
+}
+
+// List returns a slice of postmand.ReplayJob by options filter.
+func (r ReplayJob) List(ctx context.Context, listOptions postmand.RepositoryListOptions) ([]*postmand.ReplayJob, error) {
+	return r.replayJobRepository.List(ctx, listOptions)
+}
+
+// Create postmand.ReplayJob on database, the job is executed later by the workers.
+func (r ReplayJob) Create(ctx context.Context, replayJob *postmand.ReplayJob) error {
+	now := time.Now().UTC()
+	replayJob.ID = uuid.New()
+	replayJob.Status = postmand.ReplayJobStatusPending
+	replayJob.ReplayedDeliveries = 0
+	replayJob.Error = ""
+	replayJob.CreatedAt = now
+	replayJob.UpdatedAt = now
+	return r.replayJobRepository.Create(ctx, replayJob)
+}
+
+// Run executes a pending postmand.ReplayJob.
+func (r ReplayJob) Run(ctx context.Context, id postmand.ID) (*postmand.ReplayJob, error) {
+	return r.replayJobRepository.Run(ctx, id)
+}
+
+// NewReplayJob will create an implementation of postmand.ReplayJobService.
+func NewReplayJob(replayJobRepository postmand.ReplayJobRepository) *ReplayJob {
+	return &ReplayJob{replayJobRepository: replayJobRepository}
+}
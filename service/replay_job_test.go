@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/allisson/postmand"
+	"github.com/allisson/postmand/mocks"
+)
+
+func TestReplayJob(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Get", func(t *testing.T) {
+		replayJobRepository := &mocks.ReplayJobRepository{}
+		replayJobService := NewReplayJob(replayJobRepository)
+		expectedReplayJob := &postmand.ReplayJob{ID: uuid.New()}
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": expectedReplayJob.ID}}
+
+		replayJobRepository.On("Get", mock.Anything, getOptions).Return(expectedReplayJob, nil)
+		replayJob, err := replayJobService.Get(ctx, getOptions)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedReplayJob, replayJob)
+		replayJobRepository.AssertExpectations(t)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		replayJobRepository := &mocks.ReplayJobRepository{}
+		replayJobService := NewReplayJob(replayJobRepository)
+		expectedReplayJob := &postmand.ReplayJob{ID: uuid.New()}
+		listOptions := postmand.RepositoryListOptions{Filters: map[string]interface{}{"id": expectedReplayJob.ID}, Limit: 1, Offset: 0}
+
+		replayJobRepository.On("List", mock.Anything, listOptions).Return([]*postmand.ReplayJob{expectedReplayJob}, nil)
+		replayJobs, err := replayJobService.List(ctx, listOptions)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedReplayJob, replayJobs[0])
+		replayJobRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		replayJobRepository := &mocks.ReplayJobRepository{}
+		replayJobService := NewReplayJob(replayJobRepository)
+		replayJob := &postmand.ReplayJob{Filters: postmand.ReplayJobFilters{"status": postmand.DeliveryStatusFailed}}
+
+		replayJobRepository.On("Create", mock.Anything, replayJob).Return(nil)
+		err := replayJobService.Create(ctx, replayJob)
+		assert.Nil(t, err)
+		assert.NotEqual(t, uuid.Nil, replayJob.ID)
+		assert.Equal(t, postmand.ReplayJobStatusPending, replayJob.Status)
+		assert.False(t, replayJob.CreatedAt.IsZero())
+		replayJobRepository.AssertExpectations(t)
+	})
+
+	t.Run("Run", func(t *testing.T) {
+		replayJobRepository := &mocks.ReplayJobRepository{}
+		replayJobService := NewReplayJob(replayJobRepository)
+		expectedReplayJob := &postmand.ReplayJob{ID: uuid.New(), Status: postmand.ReplayJobStatusCompleted}
+
+		replayJobRepository.On("Run", mock.Anything, expectedReplayJob.ID).Return(expectedReplayJob, nil)
+		replayJob, err := replayJobService.Run(ctx, expectedReplayJob.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedReplayJob, replayJob)
+		replayJobRepository.AssertExpectations(t)
+	})
+}
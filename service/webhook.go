@@ -2,13 +2,53 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"reflect"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 
 	"github.com/allisson/postmand"
 )
 
+func newVerificationToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func sameDestinations(webhook, storedWebhook *postmand.Webhook) bool {
+	return reflect.DeepEqual(webhook.DestinationURLs(), storedWebhook.DestinationURLs())
+}
+
+// prepareVerification defines the verification fields of the webhook, a new token is generated when
+// the verification is enabled or the destinations are changed. storedWebhook is nil on creation.
+func prepareVerification(webhook, storedWebhook *postmand.Webhook) error {
+	switch {
+	case !webhook.RequireVerification:
+		webhook.VerificationStatus = postmand.WebhookVerificationStatusNotRequired
+		webhook.VerificationToken = ""
+		webhook.VerifiedAt = nil
+	case storedWebhook == nil || !storedWebhook.RequireVerification || !sameDestinations(webhook, storedWebhook):
+		token, err := newVerificationToken()
+		if err != nil {
+			return err
+		}
+		webhook.VerificationStatus = postmand.WebhookVerificationStatusPending
+		webhook.VerificationToken = token
+		webhook.VerifiedAt = nil
+	default:
+		webhook.VerificationStatus = storedWebhook.VerificationStatus
+		webhook.VerificationToken = storedWebhook.VerificationToken
+		webhook.VerifiedAt = storedWebhook.VerifiedAt
+	}
+	return nil
+}
+
 // Webhook implements postmand.WebhookService interface.
 type Webhook struct {
 	webhookRepository postmand.WebhookRepository
@@ -28,9 +68,36 @@ func (w Webhook) List(ctx context.Context, listOptions postmand.RepositoryListOp
 func (w Webhook) Create(ctx context.Context, webhook *postmand.Webhook) error {
 	now := time.Now().UTC()
 	webhook.ID = uuid.New()
+	if webhook.Queue == "" {
+		webhook.Queue = postmand.WebhookQueueDefault
+	}
+	if webhook.EventTypes == nil {
+		webhook.EventTypes = pq.StringArray{}
+	}
+	if webhook.DeliveryFormat == "" {
+		webhook.DeliveryFormat = postmand.WebhookDeliveryFormatRaw
+	}
+	if webhook.Compression == "" {
+		webhook.Compression = postmand.WebhookCompressionNone
+	}
+	if webhook.BatchFormat == "" {
+		webhook.BatchFormat = postmand.WebhookBatchFormatJSONArray
+	}
+	if webhook.Destinations == nil {
+		webhook.Destinations = pq.StringArray{}
+	}
+	if webhook.DestinationStrategy == "" {
+		webhook.DestinationStrategy = postmand.WebhookDestinationStrategyFailover
+	}
+	if err := prepareVerification(webhook, nil); err != nil {
+		return err
+	}
 	webhook.CreatedAt = now
 	webhook.UpdatedAt = now
-	return w.webhookRepository.Create(ctx, webhook)
+	if err := w.webhookRepository.Create(ctx, webhook); err != nil {
+		return err
+	}
+	return w.sendVerification(ctx, webhook)
 }
 
 // Update postmand.Webhook on database.
@@ -40,9 +107,39 @@ func (w Webhook) Update(ctx context.Context, webhook *postmand.Webhook) error {
 	if err != nil {
 		return err
 	}
+	if webhook.Queue == "" {
+		webhook.Queue = postmand.WebhookQueueDefault
+	}
+	if webhook.EventTypes == nil {
+		webhook.EventTypes = pq.StringArray{}
+	}
+	if webhook.DeliveryFormat == "" {
+		webhook.DeliveryFormat = postmand.WebhookDeliveryFormatRaw
+	}
+	if webhook.Compression == "" {
+		webhook.Compression = postmand.WebhookCompressionNone
+	}
+	if webhook.BatchFormat == "" {
+		webhook.BatchFormat = postmand.WebhookBatchFormatJSONArray
+	}
+	if webhook.Destinations == nil {
+		webhook.Destinations = pq.StringArray{}
+	}
+	if webhook.DestinationStrategy == "" {
+		webhook.DestinationStrategy = postmand.WebhookDestinationStrategyFailover
+	}
+	if err := prepareVerification(webhook, storedWebhook); err != nil {
+		return err
+	}
 	webhook.CreatedAt = storedWebhook.CreatedAt
 	webhook.UpdatedAt = time.Now().UTC()
-	return w.webhookRepository.Update(ctx, webhook)
+	if err := w.webhookRepository.Update(ctx, webhook); err != nil {
+		return err
+	}
+	if webhook.VerificationToken == storedWebhook.VerificationToken {
+		return nil
+	}
+	return w.sendVerification(ctx, webhook)
 }
 
 // Delete postmand.Webhook on database.
@@ -50,6 +147,77 @@ func (w Webhook) Delete(ctx context.Context, id postmand.ID) error {
 	return w.webhookRepository.Delete(ctx, id)
 }
 
+// Test sends a payload to postmand.Webhook without creating a delivery, the sample payload is used if payload is empty.
+func (w Webhook) Test(ctx context.Context, id postmand.ID, payload string) (*postmand.WebhookTestResult, error) {
+	if payload == "" {
+		payload = postmand.WebhookTestPayload
+	}
+	return w.webhookRepository.Test(ctx, id, payload)
+}
+
+// Verify sends the verification challenge to postmand.Webhook that is pending verification.
+func (w Webhook) Verify(ctx context.Context, id postmand.ID) (*postmand.Webhook, error) {
+	return w.webhookRepository.Verify(ctx, id)
+}
+
+// Preview renders a payload through the payload template of postmand.Webhook, the sample payload is used if payload is empty.
+func (w Webhook) Preview(ctx context.Context, id postmand.ID, payload string) (*postmand.WebhookPreview, error) {
+	getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": id}}
+	webhook, err := w.webhookRepository.Get(ctx, getOptions)
+	if err != nil {
+		return nil, err
+	}
+	if payload == "" {
+		payload = postmand.WebhookTestPayload
+	}
+
+	webhookPreview := postmand.WebhookPreview{WebhookID: webhook.ID, Payload: payload, RenderedPayload: payload}
+	if webhook.PayloadTemplate == "" {
+		return &webhookPreview, nil
+	}
+	renderedPayload, err := postmand.RenderPayloadTemplate(webhook.PayloadTemplate, payload)
+	if err != nil {
+		webhookPreview.RenderedPayload = ""
+		webhookPreview.Error = err.Error()
+		return &webhookPreview, nil
+	}
+	webhookPreview.RenderedPayload = renderedPayload
+	return &webhookPreview, nil
+}
+
+// FilterDryRun evaluates the filter expression against the sample payload without sending it.
+func (w Webhook) FilterDryRun(ctx context.Context, filterDryRun *postmand.WebhookFilterDryRun) error {
+	filterExpression, err := postmand.ParseFilterExpression(filterDryRun.FilterExpression)
+	if err != nil {
+		return err
+	}
+	matched, err := filterExpression.Match(filterDryRun.Payload)
+	if err != nil {
+		return err
+	}
+	filterDryRun.Matched = matched
+	return nil
+}
+
+// sendVerification sends the verification challenge after the webhook is stored,
+// a failed challenge keeps the webhook pending verification and can be sent again with Verify.
+func (w Webhook) sendVerification(ctx context.Context, webhook *postmand.Webhook) error {
+	if webhook.VerificationStatus != postmand.WebhookVerificationStatusPending {
+		return nil
+	}
+	verifiedWebhook, err := w.webhookRepository.Verify(ctx, webhook.ID)
+	if err != nil {
+		if err == postmand.ErrWebhookVerificationFailed {
+			return nil
+		}
+		return err
+	}
+	webhook.VerificationStatus = verifiedWebhook.VerificationStatus
+	webhook.VerifiedAt = verifiedWebhook.VerifiedAt
+	webhook.UpdatedAt = verifiedWebhook.UpdatedAt
+	return nil
+}
+
 // NewWebhook will create an implementation of postmand.WebhookService.
 func NewWebhook(webhookRepository postmand.WebhookRepository) *Webhook {
 	return &Webhook{webhookRepository: webhookRepository}
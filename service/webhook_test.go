@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
@@ -49,6 +51,19 @@ func TestWebhook(t *testing.T) {
 		webhookRepository.On("Create", mock.Anything, webhook).Return(nil)
 		err := webhookService.Create(ctx, webhook)
 		assert.Nil(t, err)
+		assert.Equal(t, postmand.WebhookQueueDefault, webhook.Queue)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with queue", func(t *testing.T) {
+		webhookRepository := &mocks.WebhookRepository{}
+		webhookService := NewWebhook(webhookRepository)
+		webhook := &postmand.Webhook{ID: uuid.New(), Queue: "critical"}
+
+		webhookRepository.On("Create", mock.Anything, webhook).Return(nil)
+		err := webhookService.Create(ctx, webhook)
+		assert.Nil(t, err)
+		assert.Equal(t, "critical", webhook.Queue)
 		webhookRepository.AssertExpectations(t)
 	})
 
@@ -75,4 +90,190 @@ func TestWebhook(t *testing.T) {
 		assert.Nil(t, err)
 		webhookRepository.AssertExpectations(t)
 	})
+
+	t.Run("Test", func(t *testing.T) {
+		webhookRepository := &mocks.WebhookRepository{}
+		webhookService := NewWebhook(webhookRepository)
+		webhookID := uuid.New()
+		expectedWebhookTestResult := &postmand.WebhookTestResult{WebhookID: webhookID, Success: true}
+
+		webhookRepository.On("Test", mock.Anything, webhookID, `{"id": 1}`).Return(expectedWebhookTestResult, nil)
+		webhookTestResult, err := webhookService.Test(ctx, webhookID, `{"id": 1}`)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedWebhookTestResult, webhookTestResult)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Test with sample payload", func(t *testing.T) {
+		webhookRepository := &mocks.WebhookRepository{}
+		webhookService := NewWebhook(webhookRepository)
+		webhookID := uuid.New()
+		expectedWebhookTestResult := &postmand.WebhookTestResult{WebhookID: webhookID, Payload: postmand.WebhookTestPayload}
+
+		webhookRepository.On("Test", mock.Anything, webhookID, postmand.WebhookTestPayload).Return(expectedWebhookTestResult, nil)
+		webhookTestResult, err := webhookService.Test(ctx, webhookID, "")
+		assert.Nil(t, err)
+		assert.Equal(t, expectedWebhookTestResult, webhookTestResult)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with verification", func(t *testing.T) {
+		webhookRepository := &mocks.WebhookRepository{}
+		webhookService := NewWebhook(webhookRepository)
+		webhook := &postmand.Webhook{URL: "https://httpbin.org/post", RequireVerification: true}
+		verifiedAt := time.Now().UTC()
+		verifiedWebhook := &postmand.Webhook{VerificationStatus: postmand.WebhookVerificationStatusVerified, VerifiedAt: &verifiedAt}
+
+		webhookRepository.On("Create", mock.Anything, webhook).Return(nil)
+		webhookRepository.On("Verify", mock.Anything, mock.Anything).Return(verifiedWebhook, nil)
+		err := webhookService.Create(ctx, webhook)
+		assert.Nil(t, err)
+		assert.Len(t, webhook.VerificationToken, 64)
+		assert.Equal(t, postmand.WebhookVerificationStatusVerified, webhook.VerificationStatus)
+		assert.Equal(t, &verifiedAt, webhook.VerifiedAt)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Create with verification failed", func(t *testing.T) {
+		webhookRepository := &mocks.WebhookRepository{}
+		webhookService := NewWebhook(webhookRepository)
+		webhook := &postmand.Webhook{URL: "https://httpbin.org/post", RequireVerification: true}
+
+		webhookRepository.On("Create", mock.Anything, webhook).Return(nil)
+		webhookRepository.On("Verify", mock.Anything, mock.Anything).Return(webhook, postmand.ErrWebhookVerificationFailed)
+		err := webhookService.Create(ctx, webhook)
+		assert.Nil(t, err)
+		assert.Equal(t, postmand.WebhookVerificationStatusPending, webhook.VerificationStatus)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Update with url changed", func(t *testing.T) {
+		webhookRepository := &mocks.WebhookRepository{}
+		webhookService := NewWebhook(webhookRepository)
+		storedWebhook := &postmand.Webhook{
+			ID:                  uuid.New(),
+			URL:                 "https://httpbin.org/post",
+			RequireVerification: true,
+			VerificationStatus:  postmand.WebhookVerificationStatusVerified,
+			VerificationToken:   "token",
+		}
+		webhook := &postmand.Webhook{ID: storedWebhook.ID, URL: "https://httpbin.org/anything", RequireVerification: true}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(storedWebhook, nil)
+		webhookRepository.On("Update", mock.Anything, webhook).Return(nil)
+		webhookRepository.On("Verify", mock.Anything, webhook.ID).Return(webhook, postmand.ErrWebhookVerificationFailed)
+		err := webhookService.Update(ctx, webhook)
+		assert.Nil(t, err)
+		assert.Equal(t, postmand.WebhookVerificationStatusPending, webhook.VerificationStatus)
+		assert.NotEqual(t, "token", webhook.VerificationToken)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Update with destinations changed", func(t *testing.T) {
+		webhookRepository := &mocks.WebhookRepository{}
+		webhookService := NewWebhook(webhookRepository)
+		storedWebhook := &postmand.Webhook{
+			ID:                  uuid.New(),
+			URL:                 "https://httpbin.org/post",
+			Destinations:        pq.StringArray{"https://httpbin.org/anything"},
+			RequireVerification: true,
+			VerificationStatus:  postmand.WebhookVerificationStatusVerified,
+			VerificationToken:   "token",
+		}
+		webhook := &postmand.Webhook{ID: storedWebhook.ID, URL: "https://httpbin.org/post", RequireVerification: true}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(storedWebhook, nil)
+		webhookRepository.On("Update", mock.Anything, webhook).Return(nil)
+		webhookRepository.On("Verify", mock.Anything, webhook.ID).Return(webhook, postmand.ErrWebhookVerificationFailed)
+		err := webhookService.Update(ctx, webhook)
+		assert.Nil(t, err)
+		assert.Equal(t, postmand.WebhookVerificationStatusPending, webhook.VerificationStatus)
+		assert.Equal(t, postmand.WebhookDestinationStrategyFailover, webhook.DestinationStrategy)
+		assert.NotEqual(t, "token", webhook.VerificationToken)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Update with url not changed", func(t *testing.T) {
+		webhookRepository := &mocks.WebhookRepository{}
+		webhookService := NewWebhook(webhookRepository)
+		storedWebhook := &postmand.Webhook{
+			ID:                  uuid.New(),
+			URL:                 "https://httpbin.org/post",
+			RequireVerification: true,
+			VerificationStatus:  postmand.WebhookVerificationStatusVerified,
+			VerificationToken:   "token",
+		}
+		webhook := &postmand.Webhook{ID: storedWebhook.ID, URL: "https://httpbin.org/post", RequireVerification: true}
+
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(storedWebhook, nil)
+		webhookRepository.On("Update", mock.Anything, webhook).Return(nil)
+		err := webhookService.Update(ctx, webhook)
+		assert.Nil(t, err)
+		assert.Equal(t, postmand.WebhookVerificationStatusVerified, webhook.VerificationStatus)
+		assert.Equal(t, "token", webhook.VerificationToken)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Verify", func(t *testing.T) {
+		webhookRepository := &mocks.WebhookRepository{}
+		webhookService := NewWebhook(webhookRepository)
+		expectedWebhook := &postmand.Webhook{ID: uuid.New(), VerificationStatus: postmand.WebhookVerificationStatusVerified}
+
+		webhookRepository.On("Verify", mock.Anything, expectedWebhook.ID).Return(expectedWebhook, nil)
+		webhook, err := webhookService.Verify(ctx, expectedWebhook.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedWebhook, webhook)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("FilterDryRun", func(t *testing.T) {
+		webhookRepository := &mocks.WebhookRepository{}
+		webhookService := NewWebhook(webhookRepository)
+		filterDryRun := &postmand.WebhookFilterDryRun{FilterExpression: `payload.country == "BR"`, Payload: `{"country": "BR"}`}
+
+		err := webhookService.FilterDryRun(ctx, filterDryRun)
+		assert.Nil(t, err)
+		assert.True(t, filterDryRun.Matched)
+
+		filterDryRun.Payload = `{"country": "AR"}`
+		err = webhookService.FilterDryRun(ctx, filterDryRun)
+		assert.Nil(t, err)
+		assert.False(t, filterDryRun.Matched)
+	})
+
+	t.Run("Preview", func(t *testing.T) {
+		webhookRepository := &mocks.WebhookRepository{}
+		webhookService := NewWebhook(webhookRepository)
+		webhook := &postmand.Webhook{ID: uuid.New(), PayloadTemplate: `{"text": {{ json .event }}}`}
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
+		webhookPreview, err := webhookService.Preview(ctx, webhook.ID, "")
+		assert.Nil(t, err)
+		assert.Equal(t, postmand.WebhookTestPayload, webhookPreview.Payload)
+		assert.Equal(t, `{"text": "postmand.test"}`, webhookPreview.RenderedPayload)
+		assert.Equal(t, "", webhookPreview.Error)
+
+		webhookPreview, err = webhookService.Preview(ctx, webhook.ID, `{"id": 1}`)
+		assert.Nil(t, err)
+		assert.Equal(t, "", webhookPreview.RenderedPayload)
+		assert.Contains(t, webhookPreview.Error, `map has no entry for key "event"`)
+		webhookRepository.AssertExpectations(t)
+	})
+
+	t.Run("Preview without payload template", func(t *testing.T) {
+		webhookRepository := &mocks.WebhookRepository{}
+		webhookService := NewWebhook(webhookRepository)
+		webhook := &postmand.Webhook{ID: uuid.New()}
+		getOptions := postmand.RepositoryGetOptions{Filters: map[string]interface{}{"id": webhook.ID}}
+
+		webhookRepository.On("Get", mock.Anything, getOptions).Return(webhook, nil)
+		webhookPreview, err := webhookService.Preview(ctx, webhook.ID, `{"id": 1}`)
+		assert.Nil(t, err)
+		assert.Equal(t, `{"id": 1}`, webhookPreview.RenderedPayload)
+		webhookRepository.AssertExpectations(t)
+	})
 }
@@ -14,10 +14,59 @@ import (
 
 // Worker implements postmand.WorkerService interface.
 type Worker struct {
-	deliveryRepository postmand.DeliveryRepository
-	logger             *zap.Logger
-	pollingInterval    time.Duration
-	isStop             bool
+	deliveryRepository  postmand.DeliveryRepository
+	replayJobRepository postmand.ReplayJobRepository
+	logger              *zap.Logger
+	pollingInterval     time.Duration
+	dispatchOptions     postmand.RepositoryDispatchOptions
+	tasksRunAt          time.Time
+	isStop              bool
+}
+
+func (w *Worker) runReplayJobs(ctx context.Context) {
+	listOptions := postmand.RepositoryListOptions{
+		Filters: map[string]interface{}{"status": postmand.ReplayJobStatusPending},
+		Limit:   10,
+		OrderBy: "created_at",
+		Order:   "asc",
+	}
+	replayJobs, err := w.replayJobRepository.List(ctx, listOptions)
+	if err != nil {
+		w.logger.Error("worker-replay-job-list-error", zap.Error(err))
+		return
+	}
+
+	for _, pendingReplayJob := range replayJobs {
+		replayJob, err := w.replayJobRepository.Run(ctx, pendingReplayJob.ID)
+		if err != nil {
+			w.logger.Error("worker-replay-job-run-error", zap.String("id", pendingReplayJob.ID.String()), zap.Error(err))
+			continue
+		}
+		// Skip if the replay job was executed by another worker.
+		if replayJob == nil {
+			continue
+		}
+
+		// Log replay job.
+		w.logger.Info(
+			"worker-replay-job-executed",
+			zap.String("id", replayJob.ID.String()),
+			zap.String("status", replayJob.Status),
+			zap.Int("replayed_deliveries", replayJob.ReplayedDeliveries),
+			zap.String("error", replayJob.Error),
+		)
+	}
+}
+
+func (w *Worker) expireDeliveries(ctx context.Context) {
+	expired, err := w.deliveryRepository.Expire(ctx, w.dispatchOptions)
+	if err != nil {
+		w.logger.Error("worker-expire-deliveries-error", zap.Error(err))
+		return
+	}
+	if expired > 0 {
+		w.logger.Info("worker-deliveries-expired", zap.Int("expired_deliveries", expired))
+	}
 }
 
 func (w *Worker) run(ctx context.Context) {
@@ -27,8 +76,15 @@ func (w *Worker) run(ctx context.Context) {
 			break
 		}
 
+		// Run the periodic tasks at most once per polling interval.
+		if time.Since(w.tasksRunAt) >= w.pollingInterval {
+			w.expireDeliveries(ctx)
+			w.runReplayJobs(ctx)
+			w.tasksRunAt = time.Now()
+		}
+
 		// Dispatch webhook.
-		deliveryAttempt, err := w.deliveryRepository.Dispatch(ctx)
+		deliveryAttempt, err := w.deliveryRepository.Dispatch(ctx, w.dispatchOptions)
 		if err != nil {
 			w.logger.Error("worker-dispatch-error", zap.Error(err))
 			time.Sleep(w.pollingInterval)
@@ -72,7 +128,7 @@ func (w *Worker) Run(ctx context.Context) {
 		close(idleConnsClosed)
 	}()
 
-	w.logger.Info("worker-started")
+	w.logger.Info("worker-started", zap.Strings("queues", w.dispatchOptions.Queues))
 	w.run(ctx)
 
 	<-idleConnsClosed
@@ -85,11 +141,20 @@ func (w *Worker) Shutdown(ctx context.Context) {
 }
 
 // NewWorker will create an implementation of postmand.WorkerService.
-func NewWorker(deliveryRepository postmand.DeliveryRepository, logger *zap.Logger, pollingInterval time.Duration) *Worker {
+// An empty queues slice means that the worker dispatches deliveries from all queues.
+func NewWorker(
+	deliveryRepository postmand.DeliveryRepository,
+	replayJobRepository postmand.ReplayJobRepository,
+	logger *zap.Logger,
+	pollingInterval time.Duration,
+	queues []string,
+) *Worker {
 	return &Worker{
-		deliveryRepository: deliveryRepository,
-		logger:             logger,
-		pollingInterval:    pollingInterval,
-		isStop:             false,
+		deliveryRepository:  deliveryRepository,
+		replayJobRepository: replayJobRepository,
+		logger:              logger,
+		pollingInterval:     pollingInterval,
+		dispatchOptions:     postmand.RepositoryDispatchOptions{Queues: queues},
+		isStop:              false,
 	}
 }
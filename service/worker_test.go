@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
 
@@ -16,13 +17,23 @@ import (
 func TestWorker(t *testing.T) {
 	ctx := context.Background()
 	pollingInterval := 10 * time.Millisecond
+	dispatchOptions := postmand.RepositoryDispatchOptions{Queues: []string{"default"}}
+	replayJobListOptions := postmand.RepositoryListOptions{
+		Filters: map[string]interface{}{"status": postmand.ReplayJobStatusPending},
+		Limit:   10,
+		OrderBy: "created_at",
+		Order:   "asc",
+	}
 
 	t.Run("run with dispatch error", func(t *testing.T) {
 		deliveryRepository := &mocks.DeliveryRepository{}
+		replayJobRepository := &mocks.ReplayJobRepository{}
 		logger, _ := zap.NewDevelopment()
-		workerService := NewWorker(deliveryRepository, logger, pollingInterval)
+		workerService := NewWorker(deliveryRepository, replayJobRepository, logger, pollingInterval, []string{"default"})
 
-		deliveryRepository.On("Dispatch", mock.Anything).Return(nil, errors.New("error"))
+		deliveryRepository.On("Expire", mock.Anything, dispatchOptions).Return(0, nil)
+		replayJobRepository.On("List", mock.Anything, replayJobListOptions).Return([]*postmand.ReplayJob{}, nil)
+		deliveryRepository.On("Dispatch", mock.Anything, dispatchOptions).Return(nil, errors.New("error"))
 		// Wait 15 miliseconds before call shutdown.
 		go func() {
 			workerService.Shutdown(ctx)
@@ -30,14 +41,18 @@ func TestWorker(t *testing.T) {
 		workerService.run(ctx)
 
 		deliveryRepository.AssertExpectations(t)
+		replayJobRepository.AssertExpectations(t)
 	})
 
 	t.Run("run with no dispatch", func(t *testing.T) {
 		deliveryRepository := &mocks.DeliveryRepository{}
+		replayJobRepository := &mocks.ReplayJobRepository{}
 		logger, _ := zap.NewDevelopment()
-		workerService := NewWorker(deliveryRepository, logger, pollingInterval)
+		workerService := NewWorker(deliveryRepository, replayJobRepository, logger, pollingInterval, []string{"default"})
 
-		deliveryRepository.On("Dispatch", mock.Anything).Return(nil, nil)
+		deliveryRepository.On("Expire", mock.Anything, dispatchOptions).Return(0, nil)
+		replayJobRepository.On("List", mock.Anything, replayJobListOptions).Return([]*postmand.ReplayJob{}, nil)
+		deliveryRepository.On("Dispatch", mock.Anything, dispatchOptions).Return(nil, nil)
 		// Wait 15 miliseconds before call shutdown.
 		go func() {
 			workerService.Shutdown(ctx)
@@ -45,14 +60,18 @@ func TestWorker(t *testing.T) {
 		workerService.run(ctx)
 
 		deliveryRepository.AssertExpectations(t)
+		replayJobRepository.AssertExpectations(t)
 	})
 
 	t.Run("run with dispatch", func(t *testing.T) {
 		deliveryRepository := &mocks.DeliveryRepository{}
+		replayJobRepository := &mocks.ReplayJobRepository{}
 		logger, _ := zap.NewDevelopment()
-		workerService := NewWorker(deliveryRepository, logger, pollingInterval)
+		workerService := NewWorker(deliveryRepository, replayJobRepository, logger, pollingInterval, []string{"default"})
 
-		deliveryRepository.On("Dispatch", mock.Anything).Return(&postmand.DeliveryAttempt{}, nil)
+		deliveryRepository.On("Expire", mock.Anything, dispatchOptions).Return(0, nil)
+		replayJobRepository.On("List", mock.Anything, replayJobListOptions).Return([]*postmand.ReplayJob{}, nil)
+		deliveryRepository.On("Dispatch", mock.Anything, dispatchOptions).Return(&postmand.DeliveryAttempt{}, nil)
 		// Wait 15 miliseconds before call shutdown.
 		go func() {
 			workerService.Shutdown(ctx)
@@ -60,5 +79,27 @@ func TestWorker(t *testing.T) {
 		workerService.run(ctx)
 
 		deliveryRepository.AssertExpectations(t)
+		replayJobRepository.AssertExpectations(t)
+	})
+
+	t.Run("run with replay job", func(t *testing.T) {
+		deliveryRepository := &mocks.DeliveryRepository{}
+		replayJobRepository := &mocks.ReplayJobRepository{}
+		logger, _ := zap.NewDevelopment()
+		workerService := NewWorker(deliveryRepository, replayJobRepository, logger, pollingInterval, []string{"default"})
+		replayJob := &postmand.ReplayJob{ID: uuid.New(), Status: postmand.ReplayJobStatusPending}
+
+		deliveryRepository.On("Expire", mock.Anything, dispatchOptions).Return(0, nil)
+		replayJobRepository.On("List", mock.Anything, replayJobListOptions).Return([]*postmand.ReplayJob{replayJob}, nil)
+		replayJobRepository.On("Run", mock.Anything, replayJob.ID).Return(&postmand.ReplayJob{ID: replayJob.ID, Status: postmand.ReplayJobStatusCompleted, ReplayedDeliveries: 2}, nil)
+		deliveryRepository.On("Dispatch", mock.Anything, dispatchOptions).Return(nil, nil)
+		// Wait 15 miliseconds before call shutdown.
+		go func() {
+			workerService.Shutdown(ctx)
+		}()
+		workerService.run(ctx)
+
+		deliveryRepository.AssertExpectations(t)
+		replayJobRepository.AssertExpectations(t)
 	})
 }
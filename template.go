@@ -0,0 +1,46 @@
+package postmand
+
+import (
+	"bytes"
+	"encoding/json"
+	"text/template"
+)
+
+// payloadTemplateFuncs are the functions available in webhook payload templates.
+var payloadTemplateFuncs = template.FuncMap{
+	"json": func(value interface{}) (string, error) {
+		b, err := json.Marshal(value)
+		return string(b), err
+	},
+}
+
+func parsePayloadTemplate(payloadTemplate string) (*template.Template, error) {
+	return template.New("payload_template").Funcs(payloadTemplateFuncs).Option("missingkey=error").Parse(payloadTemplate)
+}
+
+// RenderPayloadTemplate executes the webhook payload template with the decoded JSON payload as data,
+// a payload that is not a valid JSON is used as a string.
+func RenderPayloadTemplate(payloadTemplate, payload string) (string, error) {
+	t, err := parsePayloadTemplate(payloadTemplate)
+	if err != nil {
+		return "", err
+	}
+	var data interface{}
+	if err := json.Unmarshal([]byte(payload), &data); err != nil {
+		data = payload
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func validatePayloadTemplate(value interface{}) error {
+	payloadTemplate, _ := value.(string)
+	if payloadTemplate == "" {
+		return nil
+	}
+	_, err := parsePayloadTemplate(payloadTemplate)
+	return err
+}
@@ -0,0 +1,31 @@
+package postmand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderPayloadTemplate(t *testing.T) {
+	var tests = []struct {
+		kind            string
+		payloadTemplate string
+		payload         string
+		expectedPayload string
+	}{
+		{"Field", `{"text": {{ json .message }}}`, `{"message": "order \"1\" paid"}`, `{"text": "order \"1\" paid"}`},
+		{"Object", `{"data": {{ json . }}}`, `{"id": 1}`, `{"data": {"id":1}}`},
+		{"Optional field", `{{ with index . "coupon" }}{{ . }}{{ else }}none{{ end }}`, `{"id": 1}`, `none`},
+		{"Not JSON payload", `{"text": {{ json . }}}`, `order paid`, `{"text": "order paid"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			payload, err := RenderPayloadTemplate(tt.payloadTemplate, tt.payload)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedPayload, payload)
+		})
+	}
+
+	_, err := RenderPayloadTemplate(`{{ .message }}`, `{"id": 1}`)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,213 @@
+package postmand
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+// webhookURLDeliveryPrefix is the prefix of the url placeholders resolved from the delivery metadata.
+const webhookURLDeliveryPrefix = "delivery."
+
+var (
+	// webhookURLPlaceholderRegex matches the url placeholders, eg: {tenant} or {customer.id}.
+	webhookURLPlaceholderRegex = regexp.MustCompile(`\{([^{}]*)\}`)
+	// webhookURLPlaceholderNameRegex matches a field name or a dot separated path of field names.
+	webhookURLPlaceholderNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)*$`)
+)
+
+// validatePathPlaceholderValue rejects the path values that change the path segments of the url,
+// the escaping keeps the dot segments and the receiver can unescape a slash.
+func validatePathPlaceholderValue(value string) error {
+	unescapedValue, err := url.PathUnescape(value)
+	if err != nil {
+		unescapedValue = value
+	}
+	if unescapedValue == "." || unescapedValue == ".." || strings.Contains(unescapedValue, "/") {
+		return errors.New(`the value must not be "." or ".." or contain "/"`)
+	}
+	return nil
+}
+
+// replaceURLPlaceholders replaces each placeholder with the escaped value returned by resolve,
+// the values are query escaped after the "?" and path escaped before it.
+func replaceURLPlaceholders(rawURL string, resolve func(name string) (string, error)) (string, error) {
+	queryIndex := strings.IndexByte(rawURL, '?')
+	var buf strings.Builder
+	last := 0
+	for _, match := range webhookURLPlaceholderRegex.FindAllStringSubmatchIndex(rawURL, -1) {
+		name := rawURL[match[2]:match[3]]
+		if !webhookURLPlaceholderNameRegex.MatchString(name) {
+			return "", fmt.Errorf("invalid placeholder %q", rawURL[match[0]:match[1]])
+		}
+		value, err := resolve(name)
+		if err != nil {
+			return "", fmt.Errorf("url placeholder {%s}: %v", name, err)
+		}
+		if queryIndex >= 0 && match[0] > queryIndex {
+			value = url.QueryEscape(value)
+		} else {
+			if err := validatePathPlaceholderValue(value); err != nil {
+				return "", fmt.Errorf("url placeholder {%s}: %v", name, err)
+			}
+			value = url.PathEscape(value)
+		}
+		buf.WriteString(rawURL[last:match[0]])
+		buf.WriteString(value)
+		last = match[1]
+	}
+	buf.WriteString(rawURL[last:])
+	return buf.String(), nil
+}
+
+// webhookURLPathIndex returns the index where the url path starts or the url length when it has no path,
+// the scheme, the user info, the host and the port are before this index.
+func webhookURLPathIndex(rawURL string) int {
+	start := 0
+	if i := strings.Index(rawURL, "://"); i >= 0 {
+		start = i + len("://")
+	}
+	if i := strings.IndexAny(rawURL[start:], "/?#"); i >= 0 {
+		return start + i
+	}
+	return len(rawURL)
+}
+
+// validateURLPlaceholderPositions rejects the placeholders before the url path,
+// the payload must not choose the scheme or the host that receives the request.
+func validateURLPlaceholderPositions(rawURL string) error {
+	pathIndex := webhookURLPathIndex(rawURL)
+	for _, match := range webhookURLPlaceholderRegex.FindAllStringIndex(rawURL, -1) {
+		if match[0] < pathIndex {
+			return fmt.Errorf("placeholder %q is only allowed in the url path or query", rawURL[match[0]:match[1]])
+		}
+	}
+	return nil
+}
+
+// HasURLPlaceholders returns true if the webhook url contains placeholders.
+func HasURLPlaceholders(rawURL string) bool {
+	return webhookURLPlaceholderRegex.MatchString(rawURL)
+}
+
+// deliveryMetadata returns the value of the delivery.* placeholders.
+func deliveryMetadata(delivery *Delivery, name string) (string, error) {
+	switch strings.TrimPrefix(name, webhookURLDeliveryPrefix) {
+	case "id":
+		return delivery.ID.String(), nil
+	case "webhook_id":
+		return delivery.WebhookID.String(), nil
+	case "event_id":
+		if delivery.EventID == nil {
+			return "", errors.New("the delivery has no event_id")
+		}
+		return delivery.EventID.String(), nil
+	case "event_type":
+		if delivery.EventType == "" {
+			return "", errors.New("the delivery has no event_type")
+		}
+		return delivery.EventType, nil
+	}
+	return "", errors.New("unknown delivery field")
+}
+
+// payloadField returns the value of a payload field as a string, only strings, numbers and booleans are accepted.
+func payloadField(payload interface{}, name string) (string, error) {
+	value := payload
+	for _, field := range strings.Split(name, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return "", errors.New("payload field not found")
+		}
+		if value, ok = object[field]; !ok {
+			return "", errors.New("payload field not found")
+		}
+	}
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return "", errors.New("payload field is empty")
+		}
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return fmt.Sprintf("%t", v), nil
+	}
+	return "", errors.New("payload field must be a string, a number or a boolean")
+}
+
+// ResolveWebhookURL replaces the webhook url placeholders with the delivery metadata ({delivery.id}, {delivery.webhook_id},
+// {delivery.event_id} and {delivery.event_type}) or with the JSON payload fields ({tenant} or {customer.id}).
+// The resolved url must be a valid url with the scheme and the host of the webhook url, a url without placeholders
+// is returned unchanged.
+func ResolveWebhookURL(rawURL string, delivery *Delivery) (string, error) {
+	if !HasURLPlaceholders(rawURL) {
+		return rawURL, nil
+	}
+	if err := validateURLPlaceholderPositions(rawURL); err != nil {
+		return "", err
+	}
+	var payload interface{}
+	payloadErr := errors.New("payload is not a valid JSON")
+	if delivery.PayloadEncoding != DeliveryPayloadEncodingBase64 {
+		decoder := json.NewDecoder(bytes.NewBufferString(delivery.Payload))
+		decoder.UseNumber()
+		if err := decoder.Decode(&payload); err == nil {
+			payloadErr = nil
+		}
+	}
+	resolvedURL, err := replaceURLPlaceholders(rawURL, func(name string) (string, error) {
+		if strings.HasPrefix(name, webhookURLDeliveryPrefix) {
+			return deliveryMetadata(delivery, name)
+		}
+		if payloadErr != nil {
+			return "", payloadErr
+		}
+		return payloadField(payload, name)
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := is.URL.Validate(resolvedURL); err != nil {
+		return "", fmt.Errorf("url %q: %v", resolvedURL, err)
+	}
+
+	// The placeholders are escaped, this check makes sure that a value can't change the destination of the request
+	templateURL, err := url.Parse(rawURL[:webhookURLPathIndex(rawURL)])
+	if err != nil {
+		return "", fmt.Errorf("url %q: %v", rawURL, err)
+	}
+	parsedURL, err := url.Parse(resolvedURL)
+	if err != nil {
+		return "", fmt.Errorf("url %q: %v", resolvedURL, err)
+	}
+	if parsedURL.Scheme != templateURL.Scheme || parsedURL.Host != templateURL.Host {
+		return "", fmt.Errorf("url %q: the scheme and the host must match the webhook url", resolvedURL)
+	}
+	return resolvedURL, nil
+}
+
+// validateWebhookURL validates the placeholder names and positions and the url with a sample value in each placeholder.
+func validateWebhookURL(value interface{}) error {
+	rawURL, _ := value.(string)
+	if rawURL == "" {
+		return nil
+	}
+	if err := validateURLPlaceholderPositions(rawURL); err != nil {
+		return err
+	}
+	sampleURL, err := replaceURLPlaceholders(rawURL, func(name string) (string, error) {
+		return "placeholder", nil
+	})
+	if err != nil {
+		return err
+	}
+	return is.URL.Validate(sampleURL)
+}
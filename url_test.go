@@ -0,0 +1,66 @@
+package postmand
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveWebhookURL(t *testing.T) {
+	eventID := uuid.MustParse("5b3e2f0a-6c1d-4c55-9f0e-2a4f7c1d9b11")
+	delivery := Delivery{
+		ID:        uuid.MustParse("a6e9a525-ac5a-488c-b118-bd7327ce6d8d"),
+		WebhookID: uuid.MustParse("0f2b6e0c-1a6e-4d8b-9b7c-5f1e3c2a4d6b"),
+		Payload:   `{"tenant": "acme", "customer": {"id": 1234, "name": "John Doe", "vip": true}, "tags": ["a"], "empty": "", "parent": "..", "current": ".", "path": "a/b", "escaped_path": "a%2Fb"}`,
+		EventID:   &eventID,
+		EventType: "order.created",
+	}
+	var tests = []struct {
+		kind          string
+		url           string
+		delivery      Delivery
+		expectedURL   string
+		expectedError string
+	}{
+		{"Without placeholders", "https://api.example.com/events", delivery, "https://api.example.com/events", ""},
+		{"Payload field", "https://api.example.com/tenants/{tenant}/events", delivery, "https://api.example.com/tenants/acme/events", ""},
+		{"Nested payload fields", "https://api.example.com/customers/{customer.id}/{customer.vip}", delivery, "https://api.example.com/customers/1234/true", ""},
+		{"Path escape", "https://api.example.com/customers/{customer.name}", delivery, "https://api.example.com/customers/John%20Doe", ""},
+		{"Query escape", "https://api.example.com/events?customer={customer.name}", delivery, "https://api.example.com/events?customer=John+Doe", ""},
+		{"Parent dot segment", "https://api.example.com/tenants/{parent}/events", delivery, "", `url placeholder {parent}: the value must not be "." or ".." or contain "/"`},
+		{"Current dot segment", "https://api.example.com/tenants/{current}/events", delivery, "", `url placeholder {current}: the value must not be "." or ".." or contain "/"`},
+		{"Slash", "https://api.example.com/tenants/{path}/events", delivery, "", `url placeholder {path}: the value must not be "." or ".." or contain "/"`},
+		{"Escaped slash", "https://api.example.com/tenants/{escaped_path}/events", delivery, "", `url placeholder {escaped_path}: the value must not be "." or ".." or contain "/"`},
+		{"Dot segment and slash in the query", "https://api.example.com/events?parent={parent}&path={path}", delivery, "https://api.example.com/events?parent=..&path=a%2Fb", ""},
+		{"Host placeholder", "https://{tenant}.example.com/events", delivery, "", `placeholder "{tenant}" is only allowed in the url path or query`},
+		{"Port placeholder", "https://api.example.com:{customer.id}/events", delivery, "", `placeholder "{customer.id}" is only allowed in the url path or query`},
+		{"Host without path placeholder", "https://api.example.com{tenant}", delivery, "", `placeholder "{tenant}" is only allowed in the url path or query`},
+		{
+			"Delivery metadata",
+			"https://api.example.com/{delivery.event_type}/{delivery.event_id}?delivery={delivery.id}&webhook={delivery.webhook_id}",
+			delivery,
+			"https://api.example.com/order.created/5b3e2f0a-6c1d-4c55-9f0e-2a4f7c1d9b11?delivery=a6e9a525-ac5a-488c-b118-bd7327ce6d8d&webhook=0f2b6e0c-1a6e-4d8b-9b7c-5f1e3c2a4d6b",
+			"",
+		},
+		{"Missing payload field", "https://api.example.com/{customer.email}", delivery, "", "url placeholder {customer.email}: payload field not found"},
+		{"Empty payload field", "https://api.example.com/{empty}", delivery, "", "url placeholder {empty}: payload field is empty"},
+		{"Array payload field", "https://api.example.com/{tags}", delivery, "", "url placeholder {tags}: payload field must be a string, a number or a boolean"},
+		{"Missing delivery metadata", "https://api.example.com/{delivery.event_type}", Delivery{Payload: `{}`}, "", "url placeholder {delivery.event_type}: the delivery has no event_type"},
+		{"Unknown delivery metadata", "https://api.example.com/{delivery.status}", delivery, "", "url placeholder {delivery.status}: unknown delivery field"},
+		{"Payload is not a JSON", "https://api.example.com/{tenant}", Delivery{Payload: `tenant=acme`}, "", "url placeholder {tenant}: payload is not a valid JSON"},
+		{"Invalid placeholder", "https://api.example.com/{tenant-id}", delivery, "", `invalid placeholder "{tenant-id}"`},
+		{"Scheme placeholder", "{tenant}://api.example.com", delivery, "", `placeholder "{tenant}" is only allowed in the url path or query`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			url, err := ResolveWebhookURL(tt.url, &tt.delivery)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expectedURL, url)
+		})
+	}
+}